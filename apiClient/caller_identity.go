@@ -0,0 +1,120 @@
+package apiClient
+
+import (
+	"errors"
+	"fmt"
+	"github.com/terraform-providers/terraform-provider-turbot/helpers"
+	"strings"
+)
+
+const (
+	turbotPermissionTypeAka  = "tmod:@turbot/turbot-iam#/permission/types/turbot"
+	adminPermissionLevelAka  = "tmod:@turbot/turbot-iam#/permission/levels/admin"
+	ownerPermissionLevelAka  = "tmod:@turbot/turbot-iam#/permission/levels/owner"
+	activeIdentityStatus     = "active"
+	authorisationFailedError = "authorisation failed. Verify workspace, access_key and secret_access_key have been set correctly"
+)
+
+// read the identity of the caller, i.e. the profile the access key belongs to, along with its active grants
+func (client *Client) ReadCallerIdentity() (*CallerIdentity, error) {
+	identity, err := client.readCallerIdentityResource()
+	if err != nil {
+		return nil, err
+	}
+	grants, err := client.ReadActiveGrants(identity.Turbot.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CallerIdentity{
+		Id:        identity.Turbot.Id,
+		ProfileId: identity.ProfileId,
+		Email:     identity.Email,
+		Status:    identity.Status,
+		Title:     identity.Turbot.Title,
+		Akas:      identity.Turbot.Akas,
+		Directory: identity.Turbot.ParentId,
+		Grants:    grants,
+	}, nil
+}
+
+func (client *Client) readCallerIdentityResource() (*CallerIdentityResource, error) {
+	query, responseData := validationQuery()
+
+	// execute api call
+	if err := client.doRequest(query, nil, &responseData); err != nil {
+		return nil, err
+	}
+	if !responseData.isValid() {
+		return nil, errors.New(authorisationFailedError)
+	}
+	return &responseData.Actor.Identity, nil
+}
+
+// read the active grants for an identity, fetching every page
+func (client *Client) ReadActiveGrants(identityId string) ([]Grant, error) {
+	var grants []Grant
+	paging := ""
+	for {
+		query := readActiveGrantsQuery(identityId, paging)
+		responseData := &ReadActiveGrantsResponse{}
+
+		// execute api call
+		if err := client.doRequest(query, nil, responseData); err != nil {
+			return nil, fmt.Errorf("error reading active grants: %s", err.Error())
+		}
+		for _, item := range responseData.ActiveGrants.Items {
+			grants = append(grants, item.Grant)
+		}
+		paging = responseData.ActiveGrants.Paging.Next
+		if paging == "" {
+			return grants, nil
+		}
+	}
+}
+
+// check the caller has Turbot/Admin (or Turbot/Owner) on the given resource or one of its ancestors
+func (client *Client) ValidateAdmin(resourceAka string) error {
+	identity, err := client.ReadCallerIdentity()
+	if err != nil {
+		return err
+	}
+	target, err := client.ReadResource(resourceAka, nil)
+	if err != nil {
+		return fmt.Errorf("failed to read resource %s to validate permissions: %s", resourceAka, err.Error())
+	}
+
+	// resolve the ids of the permission type and levels
+	permissionIds, err := client.ResolveResourceIds(turbotPermissionTypeAka, adminPermissionLevelAka, ownerPermissionLevelAka)
+	if err != nil {
+		return err
+	}
+	permissionTypeId := permissionIds[0]
+	permissionLevelIds := permissionIds[1:]
+
+	// the resource path is the '.' separated list of ids of the resource and its ancestors
+	resourceIds := strings.Split(target.Turbot.Path, ".")
+	resourceIds = append(resourceIds, target.Turbot.Id)
+
+	for _, grant := range identity.Grants {
+		if grant.PermissionTypeId == permissionTypeId &&
+			helpers.SliceContains(permissionLevelIds, grant.PermissionLevelId) &&
+			helpers.SliceContains(resourceIds, grant.Turbot.ResourceId) {
+			return nil
+		}
+	}
+	return fmt.Errorf("identity %s (%s) does not have Turbot/Admin permissions on %s. Grant Turbot/Admin or Turbot/Owner on this resource or one of its ancestors", identity.Title, identity.Id, resourceAka)
+}
+
+// ResolveResourceIds returns the ids of the resources with the given akas
+func (client *Client) ResolveResourceIds(akas ...string) ([]string, error) {
+	var ids []string
+	for _, aka := range akas {
+		resource, err := client.ReadResource(aka, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %s", aka, err.Error())
+		}
+		ids = append(ids, resource.Turbot.Id)
+	}
+	return ids, nil
+}
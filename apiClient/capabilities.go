@@ -0,0 +1,78 @@
+package apiClient
+
+import (
+	"fmt"
+	"github.com/terraform-providers/terraform-provider-turbot/helpers"
+	"log"
+)
+
+// WorkspaceCapabilities describes the Turbot version and GraphQL schema features of the workspace
+type WorkspaceCapabilities struct {
+	// version of the tmod:@turbot/turbot mod
+	Version string
+	// fields of the SmartFolder GraphQL type
+	SmartFolderFields []string
+	// fields of the Grant GraphQL type
+	GrantFields []string
+}
+
+// DetectCapabilities reads the Turbot version and schema features of the workspace
+// queries are adapted to the detected capabilities. If detection fails, the most compatible queries are used
+func (client *Client) DetectCapabilities() error {
+	query := capabilitiesQuery()
+	responseData := &CapabilitiesResponse{}
+
+	// execute api call
+	if err := client.doRequest(query, nil, responseData); err != nil {
+		return fmt.Errorf("error detecting workspace capabilities: %s", err.Error())
+	}
+
+	capabilities := &WorkspaceCapabilities{Version: responseData.TurbotMod.Version}
+	for _, field := range responseData.SmartFolderType.Fields {
+		capabilities.SmartFolderFields = append(capabilities.SmartFolderFields, field.Name)
+	}
+	for _, field := range responseData.GrantType.Fields {
+		capabilities.GrantFields = append(capabilities.GrantFields, field.Name)
+	}
+	log.Printf("[INFO] Turbot workspace version: %s, smart folder fields: %v, grant fields: %v", capabilities.Version, capabilities.SmartFolderFields, capabilities.GrantFields)
+	client.capabilities = capabilities
+	return nil
+}
+
+// do smart folder mutations support returning the filters and description of the smart folder?
+func (client *Client) supportsSmartFolderFilters() bool {
+	if client.capabilities == nil {
+		return false
+	}
+	return helpers.SliceContains(client.capabilities.SmartFolderFields, "filters") &&
+		helpers.SliceContains(client.capabilities.SmartFolderFields, "description")
+}
+
+// SupportsGrantExpiry returns whether the workspace enforces the validity period of grants
+// if not, grant expiry is only enforced by Terraform removing expired grants
+func (client *Client) SupportsGrantExpiry() bool {
+	if client.capabilities == nil {
+		return false
+	}
+	return helpers.SliceContains(client.capabilities.GrantFields, "validFromTimestamp") &&
+		helpers.SliceContains(client.capabilities.GrantFields, "validToTimestamp")
+}
+
+// CanSetSmartFolderFilters returns whether smart folder filters and description may be passed to the smart folder mutations
+// if the capabilities were not detected, they are assumed to be supported
+func (client *Client) CanSetSmartFolderFilters() bool {
+	return client.capabilities == nil || client.supportsSmartFolderFilters()
+}
+
+// CheckSmartFolderFilters returns an error if the workspace does not support setting smart folder filters and description
+// if the capabilities were not detected, no check is made
+func (client *Client) CheckSmartFolderFilters() error {
+	if client.CanSetSmartFolderFilters() {
+		return nil
+	}
+	version := client.capabilities.Version
+	if version == "" {
+		version = "unknown"
+	}
+	return fmt.Errorf("the workspace does not support smart folder filters and description (Turbot version %s). Update the tmod:@turbot/turbot mod to use these arguments", version)
+}
@@ -0,0 +1,52 @@
+package apiClient
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestCheckSmartFolderFilters(t *testing.T) {
+	type test struct {
+		name         string
+		capabilities *WorkspaceCapabilities
+		valid        bool
+	}
+	tests := []test{
+		{"Capabilities not detected", nil, true},
+		{"Filters supported", &WorkspaceCapabilities{Version: "5.12.3", SmartFolderFields: []string{"description", "filters"}}, true},
+		{"Filters not supported", &WorkspaceCapabilities{Version: "5.0.0", SmartFolderFields: []string{"turbot"}}, false},
+	}
+	for _, test := range tests {
+		client := &Client{capabilities: test.capabilities}
+		assert.Equal(t, test.valid, client.CanSetSmartFolderFilters(), test.name)
+		err := client.CheckSmartFolderFilters()
+		if test.valid {
+			assert.Nil(t, err, test.name)
+		} else if assert.NotNil(t, err, test.name) {
+			assert.Contains(t, err.Error(), "Turbot version 5.0.0", test.name)
+		}
+	}
+}
+
+func TestSmartFolderMutationFilters(t *testing.T) {
+	client := &Client{}
+	assert.False(t, client.supportsSmartFolderFilters())
+	assert.False(t, strings.Contains(createSmartFolderMutation(client.supportsSmartFolderFilters()), "filters"))
+
+	client.capabilities = &WorkspaceCapabilities{SmartFolderFields: []string{"turbot", "description", "filters"}}
+	assert.True(t, client.supportsSmartFolderFilters())
+	assert.True(t, strings.Contains(createSmartFolderMutation(client.supportsSmartFolderFilters()), "filters"))
+	assert.True(t, strings.Contains(updateSmartFolderMutation(client.supportsSmartFolderFilters()), "description"))
+}
+
+func TestGrantValidityQueries(t *testing.T) {
+	client := &Client{}
+	assert.False(t, client.SupportsGrantExpiry())
+	assert.False(t, strings.Contains(readGrantQuery("123", client.SupportsGrantExpiry()), "validToTimestamp"))
+
+	client.capabilities = &WorkspaceCapabilities{GrantFields: []string{"turbot", "validFromTimestamp", "validToTimestamp"}}
+	assert.True(t, client.SupportsGrantExpiry())
+	assert.True(t, strings.Contains(readGrantQuery("123", client.SupportsGrantExpiry()), "validToTimestamp"))
+	assert.True(t, strings.Contains(readGrantListQuery("resourceId:123", "", client.SupportsGrantExpiry()), "validFromTimestamp"))
+}
@@ -5,11 +5,8 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
-	"github.com/go-yaml/yaml"
 	"github.com/machinebox/graphql"
 	"github.com/mitchellh/go-homedir"
-	"io/ioutil"
-	"log"
 	"net/url"
 	"os"
 	"path"
@@ -17,40 +14,104 @@ import (
 	"regexp"
 	"runtime"
 	"strings"
+	"sync"
+	"time"
 )
 
 // Turbot API Client
 type Client struct {
 	AccessKey string
 	SecretKey string
+	// the workspace api url
+	Workspace string
 	Graphql   *graphql.Client
+	// where each of the credentials was obtained from
+	CredentialsSources CredentialsSources
+	// log redacted details of each GraphQL request - enabled by setting TF_LOG_TURBOT_HTTP
+	httpLogging bool
+	// if credentials were obtained from a credential_process, the command and expiry used to refresh them
+	credentialProcess string
+	expiration        time.Time
+	credentialsLock   sync.Mutex
+	// Turbot version and schema features of the workspace - nil until DetectCapabilities is called
+	capabilities *WorkspaceCapabilities
+	// tags which are managed outside Terraform
+	IgnoreTags IgnoreTagsConfig
+	// if set, creating a policy setting, mod or folder which already exists adopts the existing object
+	AdoptExisting bool
+	// the Terraform configuration which manages the objects created by the provider
+	Ownership OwnershipConfig
+	// tags and policy setting note applied to all resources, unless overridden by the resource
+	DefaultTags       map[string]string
+	DefaultPolicyNote string
+	// if set, all mutations are refused - set by the provider read_only argument or TURBOT_READ_ONLY
+	ReadOnly bool
 }
 
 func CreateClient(config ClientConfig) (*Client, error) {
 	// if accessKeyId and secretAccessKey were not directly specified (either via provider parameters or environment variables)
 	// look for a credentials file
 
-	credentials, err := GetCredentials(config)
+	credentials, sources, err := ResolveCredentials(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get credentials, error: %s", err.Error())
 	}
+	httpLogging := httpLoggingEnabled()
+	var graphqlOptions []graphql.ClientOption
+	if httpLogging {
+		graphqlOptions = append(graphqlOptions, graphql.WithHTTPClient(newLoggingHttpClient()))
+	}
 	return &Client{
-		AccessKey: credentials.AccessKey,
-		SecretKey: credentials.SecretKey,
-		Graphql:   graphql.NewClient(credentials.Workspace),
+		AccessKey:          credentials.AccessKey,
+		SecretKey:          credentials.SecretKey,
+		Workspace:          credentials.Workspace,
+		Graphql:            graphql.NewClient(credentials.Workspace, graphqlOptions...),
+		CredentialsSources: sources,
+		httpLogging:        httpLogging,
+		// credential process details are used to refresh expiring credentials
+		credentialProcess: credentials.CredentialProcess,
+		expiration:        credentials.Expiration,
+		IgnoreTags:        config.IgnoreTags,
+		AdoptExisting:     config.AdoptExisting,
+		Ownership:         config.Ownership,
+		DefaultTags:       config.DefaultTags,
+		DefaultPolicyNote: config.DefaultPolicyNote,
+		ReadOnly:          config.ReadOnly || readOnlyEnabled(),
 	}, nil
 }
 
 func GetCredentials(config ClientConfig) (ClientCredentials, error) {
-	credentials := config.Credentials
-	if len(credentials.AccessKey) == 0 {
-		credentials.AccessKey = os.Getenv("TURBOT_ACCESS_KEY")
-	}
-	if len(credentials.SecretKey) == 0 {
-		credentials.SecretKey = os.Getenv("TURBOT_SECRET_KEY")
+	credentials, _, err := ResolveCredentials(config)
+	return credentials, err
+}
+
+// ResolveCredentials determines the credentials to use and records which source supplied each of them.
+// Sources are checked in order: provider config, environment variables, credential process, credentials file.
+func ResolveCredentials(config ClientConfig) (ClientCredentials, CredentialsSources, error) {
+	var credentials ClientCredentials
+	var sources CredentialsSources
+	credentials.AccessKey, sources.AccessKey = credentialFromConfigOrEnv(config.Credentials.AccessKey, "TURBOT_ACCESS_KEY")
+	credentials.SecretKey, sources.SecretKey = credentialFromConfigOrEnv(config.Credentials.SecretKey, "TURBOT_SECRET_KEY")
+	credentials.Workspace, sources.Workspace = credentialFromConfigOrEnv(config.Credentials.Workspace, "TURBOT_WORKSPACE")
+
+	credentialProcess := config.CredentialProcess
+	if len(credentialProcess) == 0 {
+		credentialProcess = os.Getenv("TURBOT_CREDENTIAL_PROCESS")
 	}
-	if len(credentials.Workspace) == 0 {
-		credentials.Workspace = os.Getenv("TURBOT_WORKSPACE")
+
+	// if keys were not passed in but a credential process was specified, run it to get the keys
+	if (len(credentials.AccessKey) == 0 || len(credentials.SecretKey) == 0) && len(credentialProcess) != 0 {
+		var err error
+		workspaceSet := len(credentials.Workspace) != 0
+		credentials, err = credentialsFromProcess(credentialProcess, credentials)
+		if err != nil {
+			return ClientCredentials{}, CredentialsSources{}, err
+		}
+		sources.AccessKey = sourceCredentialProcess
+		sources.SecretKey = sourceCredentialProcess
+		if !workspaceSet {
+			sources.Workspace = sourceCredentialProcess
+		}
 	}
 
 	if !CredentialsSet(credentials) {
@@ -67,7 +128,7 @@ func GetCredentials(config ClientConfig) (ClientCredentials, error) {
 		} else {
 			credentialsPath, err = homedir.Expand(credentialsPath)
 			if err != nil {
-				return ClientCredentials{}, err
+				return ClientCredentials{}, CredentialsSources{}, err
 			}
 		}
 		// if no profile was provided in config, use TURBOT_PROFILE env var
@@ -76,19 +137,35 @@ func GetCredentials(config ClientConfig) (ClientCredentials, error) {
 		}
 		credentials, err = loadProfile(credentialsPath, config.Profile)
 		if err != nil {
-			return ClientCredentials{}, err
+			return ClientCredentials{}, CredentialsSources{}, err
+		}
+		fileSource := credentialsFileSource(credentialsPath, config.Profile)
+		sources = CredentialsSources{AccessKey: fileSource, SecretKey: fileSource, Workspace: fileSource}
+
+		// if the profile specifies a credential process, run it to get the keys
+		if len(credentials.CredentialProcess) != 0 {
+			workspaceSet := len(credentials.Workspace) != 0
+			credentials, err = credentialsFromProcess(credentials.CredentialProcess, credentials)
+			if err != nil {
+				return ClientCredentials{}, CredentialsSources{}, err
+			}
+			sources.AccessKey = sourceCredentialProcess
+			sources.SecretKey = sourceCredentialProcess
+			if !workspaceSet {
+				sources.Workspace = sourceCredentialProcess
+			}
 		}
 		if !CredentialsSet(credentials) {
-			return ClientCredentials{}, errors.New("failed to get credentials")
+			return ClientCredentials{}, CredentialsSources{}, errors.New("failed to get credentials")
 		}
 	}
 	var err error
 	// update workspace url
 	credentials.Workspace, err = BuildApiUrl(credentials.Workspace)
 	if err != nil {
-		return ClientCredentials{}, err
+		return ClientCredentials{}, CredentialsSources{}, err
 	}
-	return credentials, nil
+	return credentials, sources, nil
 }
 
 // convert workspace into a fully formed api url
@@ -135,14 +212,17 @@ func CredentialsSet(credentials ClientCredentials) bool {
 	return len(credentials.AccessKey) != 0 && len(credentials.SecretKey) != 0 && len(credentials.Workspace) != 0
 }
 
-// Validate checks if the API workspace URL and credentials are valid.
+// Validate checks if the API workspace URL and credentials are valid, and that the identity the credentials belong to is active.
 func (client *Client) Validate() error {
-	query, responseObject := validationQuery()
-	err := client.doRequest(query, nil, &responseObject)
-	if err == nil && !responseObject.isValid() {
-		err = errors.New("authorisation failed. Verify workspace, access_key and secret_access_key have been set correctly")
+	identity, err := client.readCallerIdentityResource()
+	if err != nil {
+		return err
 	}
-	return err
+	// NOTE: the status may not be set for all identity types
+	if identity.Status != "" && strings.ToLower(identity.Status) != activeIdentityStatus {
+		return fmt.Errorf("the identity %s (%s) the access key belongs to is not active, status: %s", identity.Turbot.Title, identity.Turbot.Id, identity.Status)
+	}
+	return nil
 }
 
 // UserHomeDir returns the home directory for the user the process is running under.
@@ -155,30 +235,6 @@ func userHomeDir() string {
 	return os.Getenv("HOME")
 }
 
-func loadProfile(credentialsPath, profile string) (ClientCredentials, error) {
-	// if no profile specified, use default
-	if len(profile) == 0 {
-		profile = "default"
-	}
-	yamlFile, err := ioutil.ReadFile(credentialsPath)
-	if err != nil {
-		return ClientCredentials{}, err
-	}
-
-	var credentialsMap = map[string]ClientCredentials{}
-
-	err = yaml.Unmarshal(yamlFile, &credentialsMap)
-	if err != nil {
-		log.Fatalf("Unmarshal: %v", err)
-	}
-	credentials := credentialsMap[profile]
-	if !CredentialsSet(credentials) {
-		return ClientCredentials{}, fmt.Errorf("failed to load all credentials for profile %s from credentials file %s", profile, credentialsPath)
-	}
-
-	return credentials, nil
-}
-
 func basicAuthHeader(username, password string) string {
 	auth := username + ":" + password
 	return "Basic " + base64.StdEncoding.EncodeToString([]byte(auth))
@@ -186,6 +242,11 @@ func basicAuthHeader(username, password string) string {
 
 // execute graphql request
 func (client *Client) doRequest(query string, vars map[string]interface{}, responseData interface{}) error {
+	// in read-only mode, refuse to make any changes to the workspace
+	if mutation, ok := mutationName(query); ok && client.ReadOnly {
+		return fmt.Errorf("the provider is in read-only mode - refusing to run mutation '%s'", mutation)
+	}
+
 	// make a request
 	req := graphql.NewRequest(query)
 
@@ -197,15 +258,45 @@ func (client *Client) doRequest(query string, vars map[string]interface{}, respo
 	}
 
 	// set header fields
+	// refresh the credentials first if they are about to expire
+	accessKey, secretKey, err := client.refreshCredentials()
+	if err != nil {
+		return err
+	}
 	req.Header.Set("Cache-Control", "no-cache")
-	req.Header.Set("Authorization", basicAuthHeader(client.AccessKey, client.SecretKey))
+	req.Header.Set("Authorization", basicAuthHeader(accessKey, secretKey))
 
 	// define a Context for the request
 	ctx := context.Background()
 
+	operation := operationName(query)
+	if client.httpLogging {
+		logRequest(operation, vars)
+		ctx = withOperation(ctx, operation)
+	}
+
 	// run it and capture the response
-	if err := client.Graphql.Run(ctx, req, &responseData); err != nil {
-		return err
+	err = client.Graphql.Run(ctx, req, &responseData)
+	if client.httpLogging {
+		logResponse(operation, err)
 	}
-	return nil
+	return err
+}
+
+// set this environment variable to run the provider in read-only mode
+const readOnlyEnvVar = "TURBOT_READ_ONLY"
+
+func readOnlyEnabled() bool {
+	value := strings.ToLower(os.Getenv(readOnlyEnvVar))
+	return value != "" && value != "0" && value != "false"
+}
+
+var mutationRegex = regexp.MustCompile(`^\s*mutation\b[^{]*\{\s*(?:\w+\s*:\s*)?(\w+)`)
+
+// if the query is a mutation, return the name of the (first) mutation field it runs, e.g. "createPolicySetting"
+func mutationName(query string) (string, bool) {
+	if match := mutationRegex.FindStringSubmatch(query); match != nil {
+		return match[1], true
+	}
+	return "", false
 }
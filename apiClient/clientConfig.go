@@ -1,13 +1,98 @@
 package apiClient
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
 type ClientConfig struct {
-	Credentials     ClientCredentials
-	CredentialsPath string
-	Profile         string
+	Credentials       ClientCredentials
+	CredentialsPath   string
+	Profile           string
+	CredentialProcess string
+	IgnoreTags        IgnoreTagsConfig
+	AdoptExisting     bool
+	Ownership         OwnershipConfig
+	DefaultTags       map[string]string
+	DefaultPolicyNote string
+	ReadOnly          bool
 }
 
 type ClientCredentials struct {
 	AccessKey string `yaml:"accessKey"`
 	SecretKey string `yaml:"secretKey"`
 	Workspace string
+	// command to run to obtain credentials
+	CredentialProcess string `yaml:"credential_process"`
+	// expiry time of credentials obtained from a credential process
+	Expiration time.Time `yaml:"-"`
+}
+
+// CredentialsSources records where each of the credentials was obtained from
+type CredentialsSources struct {
+	AccessKey string
+	SecretKey string
+	Workspace string
+}
+
+// String describes the source of each credential, for use in error messages
+func (s CredentialsSources) String() string {
+	return fmt.Sprintf("access key from %s, secret key from %s, workspace from %s", sourceOrUnset(s.AccessKey), sourceOrUnset(s.SecretKey), sourceOrUnset(s.Workspace))
+}
+
+func sourceOrUnset(source string) string {
+	if source == "" {
+		return "<not set>"
+	}
+	return source
+}
+
+// IgnoreTagsConfig defines resource tags which are managed outside Terraform, and so are excluded from diffs
+type IgnoreTagsConfig struct {
+	Keys        []string
+	KeyPrefixes []string
+}
+
+// IsIgnored returns whether the tag key matches one of the ignored keys or key prefixes
+func (config IgnoreTagsConfig) IsIgnored(key string) bool {
+	for _, ignoredKey := range config.Keys {
+		if key == ignoredKey {
+			return true
+		}
+	}
+	for _, prefix := range config.KeyPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// OwnershipConfig identifies the Terraform configuration which manages the objects created by the provider
+// it is written to the turbot.terraform metadata of each object
+type OwnershipConfig struct {
+	Owner     string
+	Workspace string
+	Module    string
+	// if set, adopting an object owned by another configuration logs a warning rather than failing
+	WarnOnConflict bool
+}
+
+// Metadata returns the turbot.terraform metadata for an object managed by the given Terraform resource type
+func (config OwnershipConfig) Metadata(resourceType string) map[string]interface{} {
+	return map[string]interface{}{
+		"owner":        config.Owner,
+		"workspace":    config.Workspace,
+		"module":       config.Module,
+		"resourceType": resourceType,
+	}
+}
+
+// OwnedByOther returns whether the turbot.terraform metadata of an object shows it is managed by a different Terraform configuration
+func (config OwnershipConfig) OwnedByOther(metadata map[string]interface{}) bool {
+	if len(metadata) == 0 {
+		return false
+	}
+	return metadata["owner"] != config.Owner || metadata["workspace"] != config.Workspace || metadata["module"] != config.Module
 }
@@ -0,0 +1,37 @@
+package apiClient
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestIgnoreTagsIsIgnored(t *testing.T) {
+	config := IgnoreTagsConfig{
+		Keys:        []string{"owner"},
+		KeyPrefixes: []string{"turbot:", "aws:"},
+	}
+	assert.True(t, config.IsIgnored("owner"))
+	assert.True(t, config.IsIgnored("turbot:managedBy"))
+	assert.True(t, config.IsIgnored("aws:cloudformation:stack-name"))
+	assert.False(t, config.IsIgnored("owner2"))
+	assert.False(t, config.IsIgnored("environment"))
+	assert.False(t, IgnoreTagsConfig{}.IsIgnored("owner"))
+}
+
+func TestCredentialsSourcesString(t *testing.T) {
+	sources := CredentialsSources{AccessKey: "environment variable TURBOT_ACCESS_KEY", SecretKey: "provider configuration"}
+	assert.Equal(t, "access key from environment variable TURBOT_ACCESS_KEY, secret key from provider configuration, workspace from <not set>", sources.String())
+}
+
+func TestOwnershipOwnedByOther(t *testing.T) {
+	config := OwnershipConfig{Owner: "network", Workspace: "prod", Module: "module.network"}
+	metadata := config.Metadata("turbot_folder")
+	assert.Equal(t, "turbot_folder", metadata["resourceType"])
+	assert.False(t, config.OwnedByOther(metadata))
+	// objects which are not stamped are not owned by any configuration
+	assert.False(t, config.OwnedByOther(nil))
+	assert.False(t, config.OwnedByOther(map[string]interface{}{}))
+	assert.True(t, config.OwnedByOther(OwnershipConfig{Owner: "network", Workspace: "dev", Module: "module.network"}.Metadata("turbot_folder")))
+	assert.True(t, config.OwnedByOther(OwnershipConfig{Owner: "security", Workspace: "prod", Module: "module.network"}.Metadata("turbot_folder")))
+	assert.True(t, config.OwnedByOther(OwnershipConfig{Owner: "network", Workspace: "prod"}.Metadata("turbot_folder")))
+}
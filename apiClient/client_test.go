@@ -101,3 +101,32 @@ func TestBuildApiUrl(t *testing.T) {
 
 	}
 }
+
+func TestMutationName(t *testing.T) {
+	type test struct {
+		name       string
+		query      string
+		expected   string
+		isMutation bool
+	}
+	tests := []test{
+		{"Create policy setting", createPolicySettingMutation(), "createPolicySetting", true},
+		{"Install mod", installModMutation(), "installMod", true},
+		{"Delete resource", deleteResourceMutation(), "deleteResource", true},
+		{"Batched attach", batchSmartFolderAttachmentMutation(false, 2), "attachSmartFolders", true},
+		{"Query", readPolicySettingQuery("123"), "", false},
+	}
+	for _, test := range tests {
+		name, isMutation := mutationName(test.query)
+		assert.Equal(t, test.isMutation, isMutation, test.name)
+		assert.Equal(t, test.expected, name, test.name)
+	}
+}
+
+func TestReadOnlyRefusesMutations(t *testing.T) {
+	client := &Client{ReadOnly: true}
+	err := client.DeletePolicySetting("123")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "read-only mode - refusing to run mutation 'deletePolicySetting'")
+	}
+}
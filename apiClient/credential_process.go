@@ -0,0 +1,97 @@
+package apiClient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// credentials obtained from a credential_process are refreshed when they are within this window of expiry
+const credentialRefreshWindow = 5 * time.Minute
+
+// the JSON document a credential_process must write to stdout
+type credentialProcessOutput struct {
+	AccessKey  string `json:"accessKey"`
+	SecretKey  string `json:"secretKey"`
+	Workspace  string `json:"workspace"`
+	Expiration string `json:"expiration"`
+}
+
+// run an external command to obtain credentials
+// the command must print JSON of the form {"accessKey": "...", "secretKey": "...", "workspace": "...", "expiration": "..."}
+// workspace and expiration are optional - expiration must be an RFC3339 timestamp
+func runCredentialProcess(command string) (ClientCredentials, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return ClientCredentials{}, fmt.Errorf("credential_process '%s' failed: %s %s", command, err.Error(), strings.TrimSpace(stderr.String()))
+	}
+
+	var output credentialProcessOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return ClientCredentials{}, fmt.Errorf("credential_process '%s' returned invalid JSON: %s", command, err.Error())
+	}
+	if len(output.AccessKey) == 0 || len(output.SecretKey) == 0 {
+		return ClientCredentials{}, fmt.Errorf("credential_process '%s' did not return an accessKey and secretKey", command)
+	}
+
+	credentials := ClientCredentials{
+		AccessKey:         output.AccessKey,
+		SecretKey:         output.SecretKey,
+		Workspace:         output.Workspace,
+		CredentialProcess: command,
+	}
+	if len(output.Expiration) != 0 {
+		expiration, err := time.Parse(time.RFC3339, output.Expiration)
+		if err != nil {
+			return ClientCredentials{}, fmt.Errorf("credential_process '%s' returned an invalid expiration '%s' - expected an RFC3339 timestamp", command, output.Expiration)
+		}
+		credentials.Expiration = expiration
+	}
+	return credentials, nil
+}
+
+// run the credential process and merge the results with any credentials which were set explicitly
+// NOTE: an explicitly set workspace takes precedence over the workspace returned by the process
+func credentialsFromProcess(command string, credentials ClientCredentials) (ClientCredentials, error) {
+	processCredentials, err := runCredentialProcess(command)
+	if err != nil {
+		return ClientCredentials{}, err
+	}
+	if len(credentials.Workspace) != 0 {
+		processCredentials.Workspace = credentials.Workspace
+	}
+	return processCredentials, nil
+}
+
+// if the client credentials were obtained from a credential_process and are about to expire, re-run the process
+// returns the access key and secret key to use for the next request
+func (client *Client) refreshCredentials() (string, string, error) {
+	client.credentialsLock.Lock()
+	defer client.credentialsLock.Unlock()
+
+	if len(client.credentialProcess) == 0 || client.expiration.IsZero() || time.Until(client.expiration) > credentialRefreshWindow {
+		return client.AccessKey, client.SecretKey, nil
+	}
+
+	credentials, err := runCredentialProcess(client.credentialProcess)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to refresh credentials: %s", err.Error())
+	}
+	client.AccessKey = credentials.AccessKey
+	client.SecretKey = credentials.SecretKey
+	client.expiration = credentials.Expiration
+	return client.AccessKey, client.SecretKey, nil
+}
@@ -0,0 +1,72 @@
+package apiClient
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func echoCredentialProcess(output string) string {
+	return fmt.Sprintf("echo '%s'", output)
+}
+
+func TestRunCredentialProcess(t *testing.T) {
+	command := echoCredentialProcess(`{"accessKey": "ak", "secretKey": "sk", "workspace": "bananaman-turbot.putney.turbot.io", "expiration": "2030-01-02T15:04:05Z"}`)
+	credentials, err := runCredentialProcess(command)
+	assert.Nil(t, err)
+	assert.Equal(t, "ak", credentials.AccessKey)
+	assert.Equal(t, "sk", credentials.SecretKey)
+	assert.Equal(t, "bananaman-turbot.putney.turbot.io", credentials.Workspace)
+	assert.Equal(t, command, credentials.CredentialProcess)
+	assert.Equal(t, time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC), credentials.Expiration.UTC())
+}
+
+func TestRunCredentialProcessErrors(t *testing.T) {
+	type test struct {
+		name    string
+		command string
+	}
+	tests := []test{
+		{"Command fails", "exit 1"},
+		{"Invalid JSON", echoCredentialProcess("not json")},
+		{"Missing secret key", echoCredentialProcess(`{"accessKey": "ak"}`)},
+		{"Invalid expiration", echoCredentialProcess(`{"accessKey": "ak", "secretKey": "sk", "expiration": "tomorrow"}`)},
+	}
+	for _, test := range tests {
+		_, err := runCredentialProcess(test.command)
+		assert.NotNil(t, err, test.name)
+	}
+}
+
+func TestCredentialsFromProcessWorkspacePrecedence(t *testing.T) {
+	command := echoCredentialProcess(`{"accessKey": "ak", "secretKey": "sk", "workspace": "process.turbot.io"}`)
+
+	credentials, err := credentialsFromProcess(command, ClientCredentials{})
+	assert.Nil(t, err)
+	assert.Equal(t, "process.turbot.io", credentials.Workspace)
+
+	credentials, err = credentialsFromProcess(command, ClientCredentials{Workspace: "config.turbot.io"})
+	assert.Nil(t, err)
+	assert.Equal(t, "config.turbot.io", credentials.Workspace)
+}
+
+func TestRefreshCredentials(t *testing.T) {
+	command := echoCredentialProcess(`{"accessKey": "new-ak", "secretKey": "new-sk", "expiration": "2030-01-02T15:04:05Z"}`)
+
+	// credentials which are not close to expiry are not refreshed
+	client := &Client{AccessKey: "ak", SecretKey: "sk", credentialProcess: command, expiration: time.Now().Add(time.Hour)}
+	accessKey, secretKey, err := client.refreshCredentials()
+	assert.Nil(t, err)
+	assert.Equal(t, "ak", accessKey)
+	assert.Equal(t, "sk", secretKey)
+
+	// credentials which are about to expire are refreshed
+	client = &Client{AccessKey: "ak", SecretKey: "sk", credentialProcess: command, expiration: time.Now().Add(time.Minute)}
+	accessKey, secretKey, err = client.refreshCredentials()
+	assert.Nil(t, err)
+	assert.Equal(t, "new-ak", accessKey)
+	assert.Equal(t, "new-sk", secretKey)
+	assert.Equal(t, "new-ak", client.AccessKey)
+	assert.Equal(t, 2030, client.expiration.Year())
+}
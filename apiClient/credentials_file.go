@@ -0,0 +1,177 @@
+package apiClient
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"github.com/go-yaml/yaml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// credential sources
+const (
+	sourceProviderConfig    = "provider configuration"
+	sourceCredentialProcess = "credential_process"
+)
+
+// map of normalised ini key names to the credential they set
+// keys are normalised by lower casing and removing underscores, and may optionally have a 'turbot' prefix,
+// so 'TURBOT_ACCESS_KEY', 'access_key' and 'accessKey' are all accepted
+var iniCredentialKeys = map[string]string{
+	"accesskey":         "accessKey",
+	"secretkey":         "secretKey",
+	"workspace":         "workspace",
+	"credentialprocess": "credential_process",
+}
+
+// return the value from config if set, otherwise from the environment variable, along with the source of the value
+func credentialFromConfigOrEnv(configValue, envVar string) (string, string) {
+	if len(configValue) != 0 {
+		return configValue, sourceProviderConfig
+	}
+	if value := os.Getenv(envVar); len(value) != 0 {
+		return value, fmt.Sprintf("environment variable %s", envVar)
+	}
+	return "", ""
+}
+
+func credentialsFileSource(credentialsPath, profile string) string {
+	return fmt.Sprintf("credentials file %s (profile %s)", credentialsPath, profileOrDefault(profile))
+}
+
+// if no profile specified, use default
+func profileOrDefault(profile string) string {
+	if len(profile) == 0 {
+		return "default"
+	}
+	return profile
+}
+
+func loadProfile(credentialsPath, profile string) (ClientCredentials, error) {
+	profile = profileOrDefault(profile)
+	fileBytes, err := ioutil.ReadFile(credentialsPath)
+	if err != nil {
+		return ClientCredentials{}, err
+	}
+
+	credentialsMap, err := parseCredentialsFile(credentialsPath, fileBytes)
+	if err != nil {
+		return ClientCredentials{}, err
+	}
+	credentials, ok := credentialsMap[profile]
+	if !ok {
+		return ClientCredentials{}, fmt.Errorf("profile %s not found in credentials file %s", profile, credentialsPath)
+	}
+	// a profile using a credential process does not need to contain keys
+	if len(credentials.CredentialProcess) != 0 {
+		return credentials, nil
+	}
+	if !CredentialsSet(credentials) {
+		return ClientCredentials{}, fmt.Errorf("failed to load all credentials for profile %s from credentials file %s", profile, credentialsPath)
+	}
+
+	return credentials, nil
+}
+
+// parse a credentials file, which may be either YAML or INI format
+// INI format is used if the file has a '.ini' extension or the first setting in the file is a [section] header
+func parseCredentialsFile(credentialsPath string, fileBytes []byte) (map[string]ClientCredentials, error) {
+	if strings.ToLower(filepath.Ext(credentialsPath)) == ".ini" || isIniFormat(fileBytes) {
+		return parseIniCredentials(credentialsPath, fileBytes)
+	}
+	return parseYamlCredentials(credentialsPath, fileBytes)
+}
+
+func parseYamlCredentials(credentialsPath string, fileBytes []byte) (map[string]ClientCredentials, error) {
+	var credentialsMap = map[string]ClientCredentials{}
+	// use strict parsing so unknown keys are reported, with their line numbers
+	if err := yaml.UnmarshalStrict(fileBytes, &credentialsMap); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials file %s: %s", credentialsPath, err.Error())
+	}
+	return credentialsMap, nil
+}
+
+func isIniFormat(fileBytes []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(fileBytes))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || isIniComment(line) {
+			continue
+		}
+		return strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]")
+	}
+	return false
+}
+
+func isIniComment(line string) bool {
+	return strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";")
+}
+
+// parse an INI format credentials file of the form:
+//
+// [default]
+// TURBOT_ACCESS_KEY=<key>
+// TURBOT_SECRET_KEY=<secret>
+// TURBOT_WORKSPACE=<workspace>
+func parseIniCredentials(credentialsPath string, fileBytes []byte) (map[string]ClientCredentials, error) {
+	var credentialsMap = map[string]ClientCredentials{}
+	var profile string
+	lineNumber := 0
+
+	scanner := bufio.NewScanner(bytes.NewReader(fileBytes))
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || isIniComment(line) {
+			continue
+		}
+		// section header
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("failed to parse credentials file %s: line %d: invalid profile header '%s'", credentialsPath, lineNumber, line)
+			}
+			profile = strings.TrimSpace(line[1 : len(line)-1])
+			if _, ok := credentialsMap[profile]; !ok {
+				credentialsMap[profile] = ClientCredentials{}
+			}
+			continue
+		}
+		// key=value
+		separatorIndex := strings.Index(line, "=")
+		if separatorIndex == -1 {
+			return nil, fmt.Errorf("failed to parse credentials file %s: line %d: expected 'key=value', got '%s'", credentialsPath, lineNumber, line)
+		}
+		if len(profile) == 0 {
+			return nil, fmt.Errorf("failed to parse credentials file %s: line %d: setting is not inside a [profile] section", credentialsPath, lineNumber)
+		}
+		key := strings.TrimSpace(line[:separatorIndex])
+		value := strings.Trim(strings.TrimSpace(line[separatorIndex+1:]), `"'`)
+
+		credentials := credentialsMap[profile]
+		switch iniCredentialKeys[normaliseIniKey(key)] {
+		case "accessKey":
+			credentials.AccessKey = value
+		case "secretKey":
+			credentials.SecretKey = value
+		case "workspace":
+			credentials.Workspace = value
+		case "credential_process":
+			credentials.CredentialProcess = value
+		default:
+			return nil, fmt.Errorf("failed to parse credentials file %s: line %d: unknown key '%s'", credentialsPath, lineNumber, key)
+		}
+		credentialsMap[profile] = credentials
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read credentials file %s: %s", credentialsPath, err.Error())
+	}
+	return credentialsMap, nil
+}
+
+func normaliseIniKey(key string) string {
+	normalised := strings.ToLower(strings.Replace(key, "_", "", -1))
+	return strings.TrimPrefix(normalised, "turbot")
+}
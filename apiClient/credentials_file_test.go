@@ -0,0 +1,174 @@
+package apiClient
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeCredentialsFile(t *testing.T, name, contents string) string {
+	dir, err := ioutil.TempDir("", "turbot-credentials")
+	if err != nil {
+		t.Fatal(err)
+	}
+	credentialsPath := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(credentialsPath, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return credentialsPath
+}
+
+func TestLoadProfile(t *testing.T) {
+	type test struct {
+		name          string
+		fileName      string
+		contents      string
+		profile       string
+		expected      ClientCredentials
+		expectedError string
+	}
+	tests := []test{
+		{
+			name:     "YAML default profile",
+			fileName: "credentials.yml",
+			contents: `
+default:
+  accessKey: ak
+  secretKey: sk
+  workspace: bananaman-turbot.putney.turbot.io
+`,
+			expected: ClientCredentials{AccessKey: "ak", SecretKey: "sk", Workspace: "bananaman-turbot.putney.turbot.io"},
+		},
+		{
+			name:     "YAML credential process",
+			fileName: "credentials.yml",
+			contents: `
+other:
+  credential_process: get-credentials
+`,
+			profile:  "other",
+			expected: ClientCredentials{CredentialProcess: "get-credentials"},
+		},
+		{
+			name:     "YAML unknown key",
+			fileName: "credentials.yml",
+			contents: `
+default:
+  accessKey: ak
+  secretKey: sk
+  workspaces: bananaman-turbot.putney.turbot.io
+`,
+			expectedError: "line 5: field workspaces not found",
+		},
+		{
+			name:     "YAML syntax error",
+			fileName: "credentials.yml",
+			contents: "default:\n  accessKey: ak\n secretKey: sk\n",
+			// the parser reports the mapping which the mis-indented key breaks
+			expectedError: "line 2: did not find expected key",
+		},
+		{
+			name:     "INI default profile",
+			fileName: "credentials",
+			contents: `
+# comment
+[default]
+TURBOT_ACCESS_KEY=ak
+TURBOT_SECRET_KEY=sk
+turbot_workspace=bananaman-turbot.putney.turbot.io
+
+[other]
+accessKey = "ak2"
+`,
+			expected: ClientCredentials{AccessKey: "ak", SecretKey: "sk", Workspace: "bananaman-turbot.putney.turbot.io"},
+		},
+		{
+			name:     "INI credential process in .ini file",
+			fileName: "credentials.ini",
+			contents: `
+[other]
+credential_process=get-credentials
+`,
+			profile:  "other",
+			expected: ClientCredentials{CredentialProcess: "get-credentials"},
+		},
+		{
+			name:     "INI unknown key",
+			fileName: "credentials",
+			contents: `[default]
+TURBOT_ACCESS_KEY=ak
+TURBOT_SECRET=sk
+`,
+			expectedError: "line 3: unknown key 'TURBOT_SECRET'",
+		},
+		{
+			name:     "INI setting outside profile",
+			fileName: "credentials.ini",
+			contents: `TURBOT_ACCESS_KEY=ak
+`,
+			expectedError: "line 1: setting is not inside a [profile] section",
+		},
+		{
+			name:     "Missing profile",
+			fileName: "credentials.yml",
+			contents: `
+default:
+  accessKey: ak
+`,
+			profile:       "missing",
+			expectedError: "profile missing not found",
+		},
+		{
+			name:     "Incomplete profile",
+			fileName: "credentials.yml",
+			contents: `
+default:
+  accessKey: ak
+`,
+			expectedError: "failed to load all credentials for profile default",
+		},
+	}
+	for _, test := range tests {
+		credentialsPath := writeCredentialsFile(t, test.fileName, test.contents)
+		credentials, err := loadProfile(credentialsPath, test.profile)
+		os.RemoveAll(filepath.Dir(credentialsPath))
+		if test.expectedError != "" {
+			if assert.NotNil(t, err, test.name) {
+				assert.Contains(t, err.Error(), test.expectedError, test.name)
+			}
+			continue
+		}
+		assert.Nil(t, err, test.name)
+		assert.Equal(t, test.expected, credentials, test.name)
+	}
+}
+
+func TestResolveCredentialsSources(t *testing.T) {
+	credentialsPath := writeCredentialsFile(t, "credentials.yml", `
+default:
+  accessKey: file-ak
+  secretKey: file-sk
+  workspace: bananaman-turbot.putney.turbot.io
+`)
+	defer os.RemoveAll(filepath.Dir(credentialsPath))
+
+	// credentials from the credentials file
+	_, sources, err := ResolveCredentials(ClientConfig{CredentialsPath: credentialsPath})
+	assert.Nil(t, err)
+	fileSource := credentialsFileSource(credentialsPath, "")
+	assert.Equal(t, CredentialsSources{AccessKey: fileSource, SecretKey: fileSource, Workspace: fileSource}, sources)
+
+	// credentials from provider config and environment variables
+	os.Setenv("TURBOT_WORKSPACE", "bananaman-turbot.putney.turbot.io")
+	defer os.Unsetenv("TURBOT_WORKSPACE")
+	config := ClientConfig{
+		Credentials:     ClientCredentials{AccessKey: "ak", SecretKey: "sk"},
+		CredentialsPath: credentialsPath,
+	}
+	credentials, sources, err := ResolveCredentials(config)
+	assert.Nil(t, err)
+	assert.Equal(t, "ak", credentials.AccessKey)
+	assert.Equal(t, CredentialsSources{AccessKey: sourceProviderConfig, SecretKey: sourceProviderConfig, Workspace: "environment variable TURBOT_WORKSPACE"}, sources)
+}
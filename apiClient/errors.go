@@ -1,6 +1,9 @@
 package apiClient
 
-import "regexp"
+import (
+	"fmt"
+	"regexp"
+)
 
 func NotFoundError(err error) bool {
 	notFoundErr := "(?i)not Found"
@@ -13,3 +16,18 @@ func FailedValidationError(err error) bool {
 	expectedErr := regexp.MustCompile(dataValidationError)
 	return expectedErr.Match([]byte(err.Error()))
 }
+
+func ConflictError(err error) bool {
+	conflictErr := "(?i)conflict"
+	expectedErr := regexp.MustCompile(conflictErr)
+	return expectedErr.Match([]byte(err.Error()))
+}
+
+// VersionConflictError explains the conflict error returned by an update whose version id precondition failed,
+// i.e. the object has been modified since the version id was read. Other errors are returned unchanged
+func VersionConflictError(objectType, id, versionId string, err error) error {
+	if err == nil || versionId == "" || !ConflictError(err) {
+		return err
+	}
+	return fmt.Errorf("conflict: %s %s has been modified outside Terraform since it was last read (expected version %s). Run terraform plan again to review the changes before applying: %s", objectType, id, versionId, err.Error())
+}
@@ -0,0 +1,40 @@
+package apiClient
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestVersionConflictError(t *testing.T) {
+	conflict := errors.New("error updating folder: Conflict: version mismatch")
+	type test struct {
+		name      string
+		versionId string
+		err       error
+		explained bool
+	}
+	tests := []test{
+		{"No error", "200101010000000001", nil, false},
+		{"Version conflict", "200101010000000001", conflict, true},
+		{"Other error", "200101010000000001", errors.New("error updating folder: Not Found"), false},
+		{"No version in state", "", conflict, false},
+	}
+	for _, test := range tests {
+		err := VersionConflictError("folder", "123", test.versionId, test.err)
+		if !test.explained {
+			assert.Equal(t, test.err, err, test.name)
+			continue
+		}
+		if assert.NotNil(t, err, test.name) {
+			assert.True(t, ConflictError(err), test.name)
+			assert.Contains(t, err.Error(), "folder 123 has been modified outside Terraform", test.name)
+			assert.Contains(t, err.Error(), "version mismatch", test.name)
+		}
+	}
+}
+
+func TestConflictError(t *testing.T) {
+	assert.True(t, ConflictError(errors.New("error updating folder: conflict: resource 123 has been modified")))
+	assert.False(t, ConflictError(errors.New("error updating folder: Not Found")))
+}
@@ -2,6 +2,7 @@ package apiClient
 
 import (
 	"fmt"
+	"strings"
 )
 
 var folderProperties = []interface{}{
@@ -14,11 +15,13 @@ var folderProperties = []interface{}{
 	"description",
 }
 
+const folderTypeUri = "tmod:@turbot/turbot#/resource/types/folder"
+
 func (client *Client) CreateFolder(input map[string]interface{}) (*Folder, error) {
 	query := createResourceMutation(folderProperties)
 	responseData := &FolderResponse{}
 	// set type in input data
-	input["type"] = "tmod:@turbot/turbot#/resource/types/folder"
+	input["type"] = folderTypeUri
 	variables := map[string]interface{}{
 		"input": input,
 	}
@@ -56,3 +59,33 @@ func (client *Client) UpdateFolder(input map[string]interface{}) (*Folder, error
 	}
 	return &responseData.Resource, nil
 }
+
+// FindFolder returns the metadata of the folder with the given title directly below the parent, or nil if there is none
+func (client *Client) FindFolder(parentAka, title string) (*TurbotResourceMetadata, error) {
+	parentIds, err := client.ResolveResourceIds(parentAka)
+	if err != nil {
+		return nil, err
+	}
+	parentId := parentIds[0]
+	// the filter may match titles inexactly, so the parent and title are also checked here
+	folder, err := client.FindResource(findFolderFilter(parentId, title), nil, func(folder Resource) bool {
+		return folder.Turbot.ParentId == parentId && folder.Turbot.Title == title
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error finding folder: %s", err.Error())
+	}
+	if folder == nil {
+		return nil, nil
+	}
+	return &folder.Turbot, nil
+}
+
+// filter the folders which are children of the parent, by title
+// NOTE: titles containing quotes or backslashes cannot be used in the filter, so only the parent is filtered on
+func findFolderFilter(parentId, title string) string {
+	filter := fmt.Sprintf("resourceTypeId:'%s' $.turbot.parentId:%s", folderTypeUri, parentId)
+	if !strings.ContainsAny(title, `'"\`) {
+		filter = fmt.Sprintf("%s $.turbot.title:'%s'", filter, title)
+	}
+	return filter
+}
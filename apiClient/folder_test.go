@@ -0,0 +1,11 @@
+package apiClient
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFindFolderFilter(t *testing.T) {
+	assert.Equal(t, "resourceTypeId:'tmod:@turbot/turbot#/resource/types/folder' $.turbot.parentId:123 $.turbot.title:'Production'", findFolderFilter("123", "Production"))
+	assert.Equal(t, "resourceTypeId:'tmod:@turbot/turbot#/resource/types/folder' $.turbot.parentId:123", findFolderFilter("123", "Bob's folder"))
+}
@@ -20,7 +20,7 @@ func (client *Client) CreateGrant(input map[string]interface{}) (*TurbotGrantMet
 }
 
 func (client *Client) ReadGrant(id string) (*Grant, error) {
-	query := readGrantQuery(id)
+	query := readGrantQuery(id, client.SupportsGrantExpiry())
 	responseData := &ReadGrantResponse{}
 
 	// execute api call
@@ -54,3 +54,44 @@ func (client *Client) GrantExists(id string) (bool, error) {
 	exists := grant.Turbot.Id != ""
 	return exists, nil
 }
+
+// FindGrant returns the grant of the given permission type and level to an identity on a resource
+// NOTE: all arguments must be ids - returns nil if no matching grant is found
+func (client *Client) FindGrant(identityId, permissionTypeId, permissionLevelId, resourceId string) (*Grant, error) {
+	grants, err := client.ReadGrantList(fmt.Sprintf("resourceId:%s profileId:%s", resourceId, identityId))
+	if err != nil {
+		return nil, err
+	}
+	for _, grant := range grants {
+		if grant.PermissionTypeId == permissionTypeId && grant.PermissionLevelId == permissionLevelId {
+			return &grant, nil
+		}
+	}
+	return nil, nil
+}
+
+// ImportId returns the composite import id of the grant, 'identity:type:level:resource'
+// the ids are resolved back to the grant by FindGrant
+func (grant *Grant) ImportId() string {
+	return fmt.Sprintf("%s:%s:%s:%s", grant.Turbot.ProfileId, grant.PermissionTypeId, grant.PermissionLevelId, grant.Turbot.ResourceId)
+}
+
+// read all grants matching the filter, reading all pages of results
+func (client *Client) ReadGrantList(filter string) ([]Grant, error) {
+	var grants []Grant
+	paging := ""
+	for {
+		query := readGrantListQuery(filter, paging, client.SupportsGrantExpiry())
+		responseData := &ReadGrantsResponse{}
+
+		// execute api call
+		if err := client.doRequest(query, nil, responseData); err != nil {
+			return nil, fmt.Errorf("error reading grants: %s", err.Error())
+		}
+		grants = append(grants, responseData.Grants.Items...)
+		paging = responseData.Grants.Paging.Next
+		if paging == "" {
+			return grants, nil
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package apiClient
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// set this environment variable to log the GraphQL operations sent to Turbot (secrets are redacted)
+const httpLogEnvVar = "TF_LOG_TURBOT_HTTP"
+
+const redactedValue = "<redacted>"
+
+// property names whose values are never written to the log
+// names are compared after lower casing and removing underscores, so both 'client_secret' and 'clientSecret' match
+var redactedProperties = []string{
+	"secretvalue",
+	"secretvaluesource",
+	"clientsecret",
+	"signatureprivatekey",
+	"accesskey",
+	"secretkey",
+	"password",
+	"authorization",
+}
+
+var namedOperationRegex = regexp.MustCompile(`^\s*(query|mutation)\s+(\w+)`)
+var anonymousOperationRegex = regexp.MustCompile(`^\s*(query|mutation)?\s*\{\s*(\w+)`)
+
+type operationContextKey struct{}
+
+// is GraphQL request logging enabled?
+func httpLoggingEnabled() bool {
+	value := strings.ToLower(os.Getenv(httpLogEnvVar))
+	return value != "" && value != "0" && value != "false"
+}
+
+// extract the operation name from a query,
+// e.g. "CreatePolicySetting" from "mutation CreatePolicySetting($input: ...) {"
+// for anonymous queries, the first field (or its alias) is used
+func operationName(query string) string {
+	if match := namedOperationRegex.FindStringSubmatch(query); match != nil {
+		return match[2]
+	}
+	if match := anonymousOperationRegex.FindStringSubmatch(query); match != nil {
+		return match[2]
+	}
+	return "unknown"
+}
+
+// return a copy of the request variables with the values of all sensitive properties replaced
+func redactVariables(vars map[string]interface{}) map[string]interface{} {
+	if vars == nil {
+		return nil
+	}
+	return redactValue(vars).(map[string]interface{})
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if isRedactedProperty(key) {
+				result[key] = redactedValue
+			} else {
+				result[key] = redactValue(item)
+			}
+		}
+		return result
+	case map[string]string:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if isRedactedProperty(key) {
+				result[key] = redactedValue
+			} else {
+				result[key] = item
+			}
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = redactValue(item)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+func isRedactedProperty(name string) bool {
+	normalised := strings.ToLower(strings.Replace(name, "_", "", -1))
+	for _, property := range redactedProperties {
+		if normalised == property {
+			return true
+		}
+	}
+	return false
+}
+
+// log the operation name and redacted variables of a GraphQL request
+func logRequest(operation string, vars map[string]interface{}) {
+	variablesJson, err := json.Marshal(redactVariables(vars))
+	if err != nil {
+		log.Printf("[DEBUG] Turbot API request: operation: %s, variables could not be serialised: %s", operation, err.Error())
+		return
+	}
+	log.Printf("[DEBUG] Turbot API request: operation: %s, variables: %s", operation, string(variablesJson))
+}
+
+// log the outcome of a GraphQL request
+func logResponse(operation string, err error) {
+	if err != nil {
+		log.Printf("[DEBUG] Turbot API response: operation: %s, status: error, error: %s", operation, err.Error())
+		return
+	}
+	log.Printf("[DEBUG] Turbot API response: operation: %s, status: success", operation)
+}
+
+// loggingTransport is an http.RoundTripper which logs the HTTP status of each GraphQL request
+// NOTE: only the status is logged - request and response bodies may contain secrets
+type loggingTransport struct {
+	transport http.RoundTripper
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(req)
+	operation, _ := req.Context().Value(operationContextKey{}).(string)
+	if err == nil {
+		log.Printf("[DEBUG] Turbot API response: operation: %s, http status: %s", operation, resp.Status)
+	}
+	return resp, err
+}
+
+func newLoggingHttpClient() *http.Client {
+	return &http.Client{
+		Transport: &loggingTransport{transport: http.DefaultTransport},
+	}
+}
+
+// add the operation name to the request context so the logging transport can include it
+func withOperation(ctx context.Context, operation string) context.Context {
+	return context.WithValue(ctx, operationContextKey{}, operation)
+}
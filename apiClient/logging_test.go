@@ -0,0 +1,73 @@
+package apiClient
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOperationName(t *testing.T) {
+	type test struct {
+		name     string
+		query    string
+		expected string
+	}
+	tests := []test{
+		{"Named mutation", createPolicySettingMutation(), "CreatePolicySetting"},
+		{"Named mutation with leading whitespace", createSmartFolderMutation(false), "CreateSmartFolder"},
+		{"Anonymous query", readPolicySettingQuery("123"), "policySetting"},
+		{"Anonymous query with alias", "{\n\tschema: __schema {\n\t\tqueryType {\n\t\t\tname\n\t\t}\n\t}\n}", "schema"},
+		{"Unparseable query", "not a query", "unknown"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, operationName(test.query), test.name)
+	}
+}
+
+func TestRedactVariables(t *testing.T) {
+	type test struct {
+		name     string
+		vars     map[string]interface{}
+		expected map[string]interface{}
+	}
+	tests := []test{
+		{
+			"No variables",
+			nil,
+			nil,
+		},
+		{
+			"No secrets",
+			map[string]interface{}{"input": map[string]interface{}{"title": "folder", "parent": "tmod:@turbot/turbot#/"}},
+			map[string]interface{}{"input": map[string]interface{}{"title": "folder", "parent": "tmod:@turbot/turbot#/"}},
+		},
+		{
+			"Nested camel case secrets",
+			map[string]interface{}{"input": map[string]interface{}{"data": map[string]interface{}{"title": "google", "clientSecret": "s3cr3t"}}},
+			map[string]interface{}{"input": map[string]interface{}{"data": map[string]interface{}{"title": "google", "clientSecret": redactedValue}}},
+		},
+		{
+			"Snake case secrets",
+			map[string]interface{}{"input": map[string]interface{}{"signature_private_key": "key", "client_secret": "s3cr3t"}},
+			map[string]interface{}{"input": map[string]interface{}{"signature_private_key": redactedValue, "client_secret": redactedValue}},
+		},
+		{
+			"String map secrets",
+			map[string]interface{}{"input": map[string]string{"id": "123", "secretValue": "s3cr3t"}},
+			map[string]interface{}{"input": map[string]interface{}{"id": "123", "secretValue": redactedValue}},
+		},
+		{
+			"Secrets in arrays",
+			map[string]interface{}{"items": []interface{}{map[string]interface{}{"accessKey": "a", "secretKey": "b", "name": "c"}}},
+			map[string]interface{}{"items": []interface{}{map[string]interface{}{"accessKey": redactedValue, "secretKey": redactedValue, "name": "c"}}},
+		},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, redactVariables(test.vars), test.name)
+	}
+}
+
+func TestRedactVariablesDoesNotModifyInput(t *testing.T) {
+	input := map[string]interface{}{"clientSecret": "s3cr3t"}
+	redactVariables(map[string]interface{}{"input": input})
+	assert.Equal(t, "s3cr3t", input["clientSecret"])
+}
@@ -5,6 +5,8 @@ import (
 	"strings"
 )
 
+const modTypeUri = "tmod:@turbot/turbot#/resource/types/mod"
+
 func (client *Client) InstallMod(input map[string]interface{}) (*InstallModData, error) {
 	query := installModMutation()
 	responseData := &InstallModResponse{}
@@ -70,6 +72,154 @@ func (client *Client) UninstallMod(modId string) error {
 	return nil
 }
 
+// CheckModUninstall returns the installed mods which depend on a mod, and the mod's resource types which have resources
+func (client *Client) CheckModUninstall(modUri string) (*ModUninstallBlockers, error) {
+	dependentMods, err := client.ReadModDependents(modUri)
+	if err != nil {
+		return nil, err
+	}
+	resourceTypes, err := client.ReadModResourceTypes(modUri)
+	if err != nil {
+		return nil, err
+	}
+	resourceTypesInUse, err := client.ReadResourceTypesInUse(resourceTypes)
+	if err != nil {
+		return nil, err
+	}
+	return &ModUninstallBlockers{DependentMods: dependentMods, ResourceTypesInUse: resourceTypesInUse}, nil
+}
+
+// ReadModDependents returns the uris of the installed mods which depend on the given mod
+func (client *Client) ReadModDependents(modUri string) ([]string, error) {
+	filter := fmt.Sprintf("resourceTypeId:'%s' resourceTypeLevel:self", modTypeUri)
+	mods, err := client.ReadResourceListAll(filter, map[string]string{"data": ""})
+	if err != nil {
+		return nil, err
+	}
+	// dependencies are keyed by mod name, e.g. '@turbot/aws'
+	modName := strings.TrimPrefix(modUri, "tmod:")
+	var dependents []string
+	for _, mod := range mods {
+		if len(mod.Turbot.Akas) == 0 || mod.Turbot.Akas[0] == modUri {
+			continue
+		}
+		if modDependsOn(mod.Data["dependencies"], modName) || modDependsOn(mod.Data["peerDependencies"], modName) {
+			dependents = append(dependents, mod.Turbot.Akas[0])
+		}
+	}
+	return dependents, nil
+}
+
+// mod dependencies are a map of mod name to version range, e.g. {"@turbot/aws": ">=5.0.0"}
+func modDependsOn(dependencies interface{}, modName string) bool {
+	dependencyMap, ok := dependencies.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	_, ok = dependencyMap[modName]
+	return ok
+}
+
+// ReadModResourceTypes returns the uris of the resource types provided by an installed mod
+func (client *Client) ReadModResourceTypes(modUri string) ([]string, error) {
+	var resourceTypes []string
+	paging := ""
+	for {
+		query := readResourceTypeListQuery(fmt.Sprintf("modUri:'%s'", modUri), paging)
+		responseData := &ResourceTypeListResponse{}
+
+		// execute api call
+		if err := client.doRequest(query, nil, responseData); err != nil {
+			return nil, fmt.Errorf("error reading resource types: %s", err.Error())
+		}
+		for _, resourceType := range responseData.ResourceTypes.Items {
+			resourceTypes = append(resourceTypes, resourceType.Uri)
+		}
+		paging = responseData.ResourceTypes.Paging.Next
+		if paging == "" {
+			return resourceTypes, nil
+		}
+	}
+}
+
+// ReadResourceTypesInUse returns the resource types from the given list which have at least one resource
+func (client *Client) ReadResourceTypesInUse(resourceTypes []string) ([]string, error) {
+	var inUse []string
+	for _, resourceType := range resourceTypes {
+		resources, err := client.ReadResourceList(fmt.Sprintf("resourceTypeId:'%s' resourceTypeLevel:self limit:1", resourceType), nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(resources) > 0 {
+			inUse = append(inUse, resourceType)
+		}
+	}
+	return inUse, nil
+}
+
+// GetModVersionResourceTypes returns the uris of the resource types provided by a version of a mod in the registry
+func (client *Client) GetModVersionResourceTypes(org, mod, version string) ([]string, error) {
+	query := modVersionResourceTypesQuery(org, mod, version)
+	responseData := &ModVersionResourceTypesResponse{}
+
+	// execute api call
+	if err := client.doRequest(query, nil, responseData); err != nil {
+		return nil, fmt.Errorf("error fetching mod version resource types: %s", err.Error())
+	}
+	var resourceTypes []string
+	for _, resourceType := range responseData.ModVersion.ResourceTypes {
+		resourceTypes = append(resourceTypes, resourceType.Uri)
+	}
+	return resourceTypes, nil
+}
+
+// ReadDroppedResourceTypesInUse returns the resource types of an installed mod which have resources,
+// but which are not provided by the given version of the mod
+func (client *Client) ReadDroppedResourceTypesInUse(modUri, version string) ([]string, error) {
+	installed, err := client.ReadModResourceTypes(modUri)
+	if err != nil {
+		return nil, err
+	}
+	org, mod := ParseModUri(modUri)
+	target, err := client.GetModVersionResourceTypes(org, mod, version)
+	if err != nil {
+		return nil, err
+	}
+	return client.ReadResourceTypesInUse(droppedResourceTypes(installed, target))
+}
+
+// return the resource types in installed which are not in target
+func droppedResourceTypes(installed, target []string) []string {
+	targetTypes := map[string]bool{}
+	for _, resourceType := range target {
+		targetTypes[resourceType] = true
+	}
+	var dropped []string
+	for _, resourceType := range installed {
+		if !targetTypes[resourceType] {
+			dropped = append(dropped, resourceType)
+		}
+	}
+	return dropped
+}
+
+// Empty returns whether there is nothing blocking the uninstall
+func (blockers *ModUninstallBlockers) Empty() bool {
+	return len(blockers.DependentMods) == 0 && len(blockers.ResourceTypesInUse) == 0
+}
+
+// Descriptions returns a description of each blocker
+func (blockers *ModUninstallBlockers) Descriptions() []string {
+	var descriptions []string
+	for _, mod := range blockers.DependentMods {
+		descriptions = append(descriptions, fmt.Sprintf("mod %s depends on this mod", mod))
+	}
+	for _, resourceType := range blockers.ResourceTypesInUse {
+		descriptions = append(descriptions, fmt.Sprintf("resources of type %s exist", resourceType))
+	}
+	return descriptions
+}
+
 func (client *Client) GetModVersions(org, mod string) ([]ModRegistryVersion, error) {
 	query := modVersionsQuery(org, mod)
 	responseData := &ModVersionResponse{}
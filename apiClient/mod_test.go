@@ -0,0 +1,52 @@
+package apiClient
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestModDependsOn(t *testing.T) {
+	type test struct {
+		name         string
+		dependencies interface{}
+		expected     bool
+	}
+	tests := []test{
+		{"Depends on mod", map[string]interface{}{"@turbot/aws": ">=5.0.0", "@turbot/turbot": ">=5.0.0"}, true},
+		{"Depends on other mods", map[string]interface{}{"@turbot/azure": ">=5.0.0"}, false},
+		{"No dependencies", nil, false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, modDependsOn(test.dependencies, "@turbot/aws"), test.name)
+	}
+}
+
+func TestDroppedResourceTypes(t *testing.T) {
+	installed := []string{
+		"tmod:@turbot/aws-s3#/resource/types/bucket",
+		"tmod:@turbot/aws-s3#/resource/types/s3",
+		"tmod:@turbot/aws-s3#/resource/types/accessPoint",
+	}
+	target := []string{
+		"tmod:@turbot/aws-s3#/resource/types/s3",
+		"tmod:@turbot/aws-s3#/resource/types/bucket",
+		"tmod:@turbot/aws-s3#/resource/types/multiRegionAccessPoint",
+	}
+	assert.Equal(t, []string{"tmod:@turbot/aws-s3#/resource/types/accessPoint"}, droppedResourceTypes(installed, target))
+	assert.Empty(t, droppedResourceTypes(installed, installed))
+}
+
+func TestModUninstallBlockers(t *testing.T) {
+	blockers := &ModUninstallBlockers{}
+	assert.True(t, blockers.Empty())
+
+	blockers = &ModUninstallBlockers{
+		DependentMods:      []string{"tmod:@turbot/aws-s3"},
+		ResourceTypesInUse: []string{"tmod:@turbot/aws#/resource/types/account"},
+	}
+	assert.False(t, blockers.Empty())
+	assert.Equal(t, []string{
+		"mod tmod:@turbot/aws-s3 depends on this mod",
+		"resources of type tmod:@turbot/aws#/resource/types/account exist",
+	}, blockers.Descriptions())
+}
@@ -75,3 +75,28 @@ func (client *Client) FindPolicySetting(policyTypeUri, resourceAka string) (Poli
 	}
 	return PolicySetting{}, nil
 }
+
+// read all policy settings made directly on a resource
+func (client *Client) ReadResourcePolicySettings(resourceId string) ([]PolicySetting, error) {
+	return client.ReadPolicySettingList(fmt.Sprintf("resource:%s level:self", resourceId))
+}
+
+// read all policy settings matching the filter, reading all pages of results
+func (client *Client) ReadPolicySettingList(filter string) ([]PolicySetting, error) {
+	var settings []PolicySetting
+	paging := ""
+	for {
+		query := readPolicySettingListQuery(filter, paging)
+		responseData := &PolicySettingListResponse{}
+
+		// execute api call
+		if err := client.doRequest(query, nil, responseData); err != nil {
+			return nil, fmt.Errorf("error reading policy settings: %s", err.Error())
+		}
+		settings = append(settings, responseData.PolicySettings.Items...)
+		paging = responseData.PolicySettings.Paging.Next
+		if paging == "" {
+			return settings, nil
+		}
+	}
+}
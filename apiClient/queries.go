@@ -43,11 +43,15 @@ func applyPrefix(prefix, inputString string) string {
 }
 
 // validation
+// read the identity of the caller - this is used to validate the credentials
 func validationQuery() (string, ValidationResponse) {
 	query := `{
-	schema: __schema {
-		queryType {
-			name
+	actor {
+		identity {
+			profileId: get(path:"profileId")
+			email: get(path:"email")
+			status: get(path:"status")
+			turbot: get(path:"turbot")
 		}
 	}
 }`
@@ -55,6 +59,43 @@ func validationQuery() (string, ValidationResponse) {
 	return query, ValidationResponse{}
 }
 
+// capabilities
+func capabilitiesQuery() string {
+	return `{
+	turbotMod: resource(id:"tmod:@turbot/turbot") {
+		version: get(path:"version")
+	}
+	smartFolderType: __type(name:"SmartFolder") {
+		fields {
+			name
+		}
+	}
+	grantType: __type(name:"Grant") {
+		fields {
+			name
+		}
+	}
+}`
+}
+
+// caller identity
+func readActiveGrantsQuery(identityId, paging string) string {
+	return fmt.Sprintf(`{
+	activeGrants(filter:"profileId:%s", paging:"%s") {
+		items {
+			grant {
+				permissionTypeId
+				permissionLevelId
+				%s
+			}
+		}
+		paging {
+			next
+		}
+	}
+}`, identityId, paging, turbotGrantMetadataFragment("\t\t\t\t"))
+}
+
 // policySetting
 func createPolicySettingMutation() string {
 	return `mutation CreatePolicySetting($input: CreatePolicySettingInput!) {
@@ -70,6 +111,7 @@ func createPolicySettingMutation() string {
 		validToTimestamp
 		turbot {
 		  id
+		  versionId
 		}
 	}
 }`
@@ -92,6 +134,7 @@ policySetting(id:"%s") {
 	turbot {
 		id
 		resourceId
+		versionId
 	}
 }
 }`, policySettingId)
@@ -111,6 +154,7 @@ func updatePolicySettingMutation() string {
 		validToTimestamp
 		turbot {
 			id
+			versionId
 		}
 	}
 }`
@@ -151,6 +195,7 @@ func findPolicySettingQuery(policyTypeUri, resourceAka string) string {
 		validToTimestamp
 		turbot {
 			id
+			terraform
 		}
     }
   }
@@ -158,6 +203,36 @@ func findPolicySettingQuery(policyTypeUri, resourceAka string) string {
 `, policyTypeUri, resourceAka)
 }
 
+// read a page of the policy settings matching a filter
+func readPolicySettingListQuery(filter, paging string) string {
+	return fmt.Sprintf(`{
+	policySettings: policySettingList(filter: "%s", paging:"%s") {
+		items {
+			value: secretValue
+			valueSource: secretValueSource
+			template
+			precedence
+			templateInput
+			input
+			note
+			validFromTimestamp
+			validToTimestamp
+			type {
+				uri
+				secret
+			}
+			turbot {
+				id
+				resourceId
+			}
+		}
+		paging {
+			next
+		}
+	}
+}`, filter, paging)
+}
+
 // policy value
 func readPolicyValueQuery(policyTypeUri string, resourceId string) string {
 	return fmt.Sprintf(`{
@@ -183,10 +258,11 @@ func readPolicyValueQuery(policyTypeUri string, resourceId string) string {
 }
 
 // smart folder
-// filter and description are removed for a workaround, will be removed after a Core change.
-func createSmartFolderMutation() string {
+// filters and description are only requested if the workspace supports them
+func createSmartFolderMutation(includeFilters bool) string {
 	return fmt.Sprintf(`mutation CreateSmartFolder($input: CreateSmartFolderInput!) {
 		smartFolder: createSmartFolder(input: $input) {
+%s
 			turbot {
 				id
 				parentId
@@ -194,7 +270,7 @@ func createSmartFolderMutation() string {
 				title
 			}
 		}
-	}`)
+	}`, smartFolderFilterProperties(includeFilters))
 }
 
 func readSmartFolderQuery(id string) string {
@@ -214,16 +290,41 @@ func readSmartFolderQuery(id string) string {
 }`, id)
 }
 
-func updateSmartFolderMutation() string {
+func updateSmartFolderMutation(includeFilters bool) string {
 	return fmt.Sprintf(`mutation UpdateSmartFolder($input: UpdateSmartFolderInput!) {
 		smartFolder: updateSmartFolder(input: $input) {
+%s
 			turbot {
 				id
 				parentId
 				akas
 			}
 		}
-	}`)
+	}`, smartFolderFilterProperties(includeFilters))
+}
+
+func smartFolderFilterProperties(includeFilters bool) string {
+	if !includeFilters {
+		return ""
+	}
+	return `			description
+			filters`
+}
+
+// read a page of the resources attached to a smart folder
+func readSmartFolderAttachedResourcesQuery(id, paging string) string {
+	return fmt.Sprintf(`{
+	smartFolder: resource(id:"%s") {
+		attachedResources(paging:"%s") {
+			items {
+				turbot: get(path:"turbot")
+			}
+			paging {
+				next
+			}
+		}
+	}
+}`, id, paging)
 }
 
 func createSmartFolderAttachmentMutation() string {
@@ -236,6 +337,28 @@ func createSmartFolderAttachmentMutation() string {
 	}`)
 }
 
+// build a mutation which attaches (or detaches) multiple resources in a single request
+// each attachment is passed as a separate input variable: $input0, $input1...
+func batchSmartFolderAttachmentMutation(detach bool, count int) string {
+	operation, mutation, inputType, alias := "AttachSmartFolders", "attachSmartFolders", "AttachSmartFolderInput", "attach"
+	if detach {
+		operation, mutation, inputType, alias = "DetachSmartFolders", "detachSmartFolders", "DetachSmartFolderInput", "detach"
+	}
+	var variables []string
+	var mutations bytes.Buffer
+	for i := 0; i < count; i++ {
+		variables = append(variables, fmt.Sprintf("$input%d: %s!", i, inputType))
+		mutations.WriteString(fmt.Sprintf(`
+	%s%d: %s(input: $input%d) {
+		turbot {
+			id
+		}
+	}`, alias, i, mutation, i))
+	}
+	return fmt.Sprintf(`mutation %s(%s) {%s
+}`, operation, strings.Join(variables, ", "), mutations.String())
+}
+
 func detachSmartFolderAttachment() string {
 	return fmt.Sprintf(`mutation DetachSmartFolder($input: DetachSmartFolderInput!) {
 		detachSmartFolder: detachSmartFolders(input: $input) {
@@ -289,6 +412,29 @@ func modVersionsQuery(org, mod string) string {
 }`, org, mod)
 }
 
+func modVersionResourceTypesQuery(org, mod, version string) string {
+	return fmt.Sprintf(`{
+	modVersion(orgName: "%s", modName: "%s", version: "%s") {
+		resourceTypes {
+			uri
+		}
+	}
+}`, org, mod, version)
+}
+
+func readResourceTypeListQuery(filter, paging string) string {
+	return fmt.Sprintf(`{
+	resourceTypes: resourceTypeList(filter: "%s", paging: "%s") {
+		items {
+			uri
+		}
+		paging {
+			next
+		}
+	}
+}`, filter, paging)
+}
+
 // resource
 func createResourceMutation(properties []interface{}) string {
 	return fmt.Sprintf(`mutation CreateResource($input: CreateResourceInput!) {
@@ -343,6 +489,27 @@ func readResourceListQuery(filter string, properties map[string]string) string {
 }`, filter, propertiesString.String())
 }
 
+// read a page of a resource list
+func readResourceListPageQuery(filter, paging string, properties map[string]string) string {
+	var propertiesString bytes.Buffer
+	if properties != nil {
+		for alias, propertyPath := range properties {
+			propertiesString.WriteString(fmt.Sprintf("\t\t\t%s: get(path: \"%s\")\n", alias, propertyPath))
+		}
+	}
+	return fmt.Sprintf(`{
+	resourceList(filter:"%s", paging:"%s") {
+		items{
+%s
+			turbot: get(path:"turbot")
+		}
+		paging {
+			next
+		}
+	}
+}`, filter, paging, propertiesString.String())
+}
+
 func readFullResourceQuery(aka string) string {
 	return fmt.Sprintf(`{
   resource(id:"%s") {
@@ -373,14 +540,40 @@ func readGoogleDirectoryQuery(aka string) string {
 }
 
 // grant
-func readGrantQuery(aka string) string {
+// the validity timestamps are only requested if the workspace supports grant expiry
+func readGrantQuery(aka string, includeValidity bool) string {
 	return fmt.Sprintf(`{
 	grant: grant(id:"%s") {
 		permissionTypeId
 		permissionLevelId
+%s
 		%s
 	}
-  }`, aka, turbotGrantMetadataFragment("\t\t"))
+  }`, aka, grantValidityProperties(includeValidity, "\t\t"), turbotGrantMetadataFragment("\t\t"))
+}
+
+// read a page of the grants matching a filter
+func readGrantListQuery(filter, paging string, includeValidity bool) string {
+	return fmt.Sprintf(`{
+	grants(filter:"%s", paging:"%s") {
+		items {
+			permissionTypeId
+			permissionLevelId
+%s
+			%s
+		}
+		paging {
+			next
+		}
+	}
+}`, filter, paging, grantValidityProperties(includeValidity, "\t\t\t"), turbotGrantMetadataFragment("\t\t\t"))
+}
+
+func grantValidityProperties(includeValidity bool, prefix string) string {
+	if !includeValidity {
+		return ""
+	}
+	return fmt.Sprintf("%svalidFromTimestamp\n%svalidToTimestamp", prefix, prefix)
 }
 
 func createGrantMutation() string {
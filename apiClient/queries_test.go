@@ -0,0 +1,32 @@
+package apiClient
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBatchSmartFolderAttachmentMutation(t *testing.T) {
+	expected := `mutation AttachSmartFolders($input0: AttachSmartFolderInput!, $input1: AttachSmartFolderInput!) {
+	attach0: attachSmartFolders(input: $input0) {
+		turbot {
+			id
+		}
+	}
+	attach1: attachSmartFolders(input: $input1) {
+		turbot {
+			id
+		}
+	}
+}`
+	assert.Equal(t, expected, batchSmartFolderAttachmentMutation(false, 2))
+
+	expected = `mutation DetachSmartFolders($input0: DetachSmartFolderInput!) {
+	detach0: detachSmartFolders(input: $input0) {
+		turbot {
+			id
+		}
+	}
+}`
+	assert.Equal(t, expected, batchSmartFolderAttachmentMutation(true, 1))
+	assert.Equal(t, "DetachSmartFolders", operationName(batchSmartFolderAttachmentMutation(true, 1)))
+}
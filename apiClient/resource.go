@@ -5,6 +5,8 @@ import (
 	"github.com/mitchellh/mapstructure"
 	"github.com/terraform-providers/terraform-provider-turbot/helpers"
 	"log"
+	"sort"
+	"strings"
 )
 
 func (client *Client) CreateResource(input map[string]interface{}) (*TurbotResourceMetadata, error) {
@@ -98,6 +100,75 @@ func (client *Client) ReadResourceList(filter string, properties map[string]stri
 	return responseData.ResourceList.Items, nil
 }
 
+// read all resources matching the filter, reading all pages of results
+func (client *Client) ReadResourceListAll(filter string, properties map[string]string) ([]Resource, error) {
+	var resources []Resource
+	paging := ""
+	for {
+		query := readResourceListPageQuery(filter, paging, properties)
+		var responseData = &ReadResourceListPageResponse{}
+
+		// execute api call
+		if err := client.doRequest(query, nil, responseData); err != nil {
+			return nil, fmt.Errorf("error fetching resource list: %s", err.Error())
+		}
+		resources = append(resources, responseData.ResourceList.Items...)
+		paging = responseData.ResourceList.Paging.Next
+		if paging == "" {
+			return resources, nil
+		}
+	}
+}
+
+// FindResource returns the first resource matching both the filter and the match function, or nil if there is none
+// pages of results are only read until a match is found
+func (client *Client) FindResource(filter string, properties map[string]string, match func(Resource) bool) (*Resource, error) {
+	paging := ""
+	for {
+		query := readResourceListPageQuery(filter, paging, properties)
+		var responseData = &ReadResourceListPageResponse{}
+
+		// execute api call
+		if err := client.doRequest(query, nil, responseData); err != nil {
+			return nil, fmt.Errorf("error fetching resource list: %s", err.Error())
+		}
+		for _, resource := range responseData.ResourceList.Items {
+			if match(resource) {
+				return &resource, nil
+			}
+		}
+		paging = responseData.ResourceList.Paging.Next
+		if paging == "" {
+			return nil, nil
+		}
+	}
+}
+
+// ReadTerraformManagedResources returns the metadata of the resources in the subtree below the given resource (inclusive)
+// which are stamped with turbot.terraform ownership metadata
+// filter is an optional additional Turbot filter, e.g. to restrict the resource types
+func (client *Client) ReadTerraformManagedResources(resourceAka, filter string) ([]TurbotResourceMetadata, error) {
+	resourceIds, err := client.ResolveResourceIds(resourceAka)
+	if err != nil {
+		return nil, err
+	}
+	subtreeFilter := fmt.Sprintf("resource:%s level:self,descendant", resourceIds[0])
+	if filter != "" {
+		subtreeFilter = fmt.Sprintf("%s %s", subtreeFilter, filter)
+	}
+	resources, err := client.ReadResourceListAll(subtreeFilter, nil)
+	if err != nil {
+		return nil, err
+	}
+	var managed []TurbotResourceMetadata
+	for _, resource := range resources {
+		if len(resource.Turbot.Terraform) > 0 {
+			managed = append(managed, resource.Turbot)
+		}
+	}
+	return managed, nil
+}
+
 func (client *Client) UpdateResource(input map[string]interface{}) (*TurbotResourceMetadata, error) {
 	query := updateResourceMutation(nil)
 	responseData := &UpdateResourceResponse{}
@@ -129,6 +200,111 @@ func (client *Client) DeleteResource(aka string) error {
 	return nil
 }
 
+// DeleteResourceTree deletes a resource along with all of its descendants, deepest first
+// the policy settings and grants on each resource are deleted before the resource itself
+// the objects which were deleted are returned, even if an error occurs part way through
+func (client *Client) DeleteResourceTree(id string) (*DeletedResources, error) {
+	deleted := &DeletedResources{}
+	resource, err := client.ReadResource(id, nil)
+	if err != nil {
+		return deleted, err
+	}
+	descendants, err := client.ReadResourceListAll(fmt.Sprintf("resource:%s level:descendant", resource.Turbot.Id), nil)
+	if err != nil {
+		return deleted, err
+	}
+	sortDeepestFirst(descendants)
+	for _, descendant := range append(descendants, *resource) {
+		if err := client.deleteResourceAndDependents(descendant.Turbot, deleted); err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
+
+// delete the policy settings and grants on a resource, then the resource, recording each deleted object
+func (client *Client) deleteResourceAndDependents(resource TurbotResourceMetadata, deleted *DeletedResources) error {
+	settings, err := client.ReadResourcePolicySettings(resource.Id)
+	if err != nil {
+		return err
+	}
+	for _, setting := range settings {
+		if err := client.DeletePolicySetting(setting.Turbot.Id); err != nil {
+			if NotFoundError(err) {
+				continue
+			}
+			return err
+		}
+		deleted.PolicySettings = append(deleted.PolicySettings, setting)
+	}
+
+	grants, err := client.ReadGrantList(fmt.Sprintf("resourceId:%s", resource.Id))
+	if err != nil {
+		return err
+	}
+	for _, grant := range grants {
+		// only delete grants made directly on this resource
+		if grant.Turbot.ResourceId != resource.Id {
+			continue
+		}
+		if err := client.DeleteGrant(grant.Turbot.Id); err != nil {
+			if NotFoundError(err) {
+				continue
+			}
+			return err
+		}
+		deleted.Grants = append(deleted.Grants, grant)
+	}
+
+	if err := client.DeleteResource(resource.Id); err != nil {
+		// the resource may already have been removed by Turbot along with one of its children
+		if NotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+	deleted.Resources = append(deleted.Resources, resource)
+	return nil
+}
+
+// sort resources so that every resource comes before its ancestors
+// the turbot path is the '.' separated list of ids from the root to the resource
+func sortDeepestFirst(resources []Resource) {
+	sort.SliceStable(resources, func(i, j int) bool {
+		return strings.Count(resources[i].Turbot.Path, ".") > strings.Count(resources[j].Turbot.Path, ".")
+	})
+}
+
+// Descriptions returns a description of each deleted object, in the order the objects were deleted within each kind
+func (deleted *DeletedResources) Descriptions() []string {
+	var descriptions []string
+	for _, setting := range deleted.PolicySettings {
+		descriptions = append(descriptions, fmt.Sprintf("policy setting %s (%s) on resource %s", setting.Turbot.Id, setting.Type.Uri, setting.Turbot.ResourceId))
+	}
+	for _, grant := range deleted.Grants {
+		descriptions = append(descriptions, fmt.Sprintf("grant %s for profile %s on resource %s", grant.Turbot.Id, grant.Turbot.ProfileId, grant.Turbot.ResourceId))
+	}
+	for _, resource := range deleted.Resources {
+		descriptions = append(descriptions, fmt.Sprintf("resource %s (%s)", resource.Id, resource.Title))
+	}
+	return descriptions
+}
+
+// Summary returns the number of each kind of object deleted, e.g. '3 resources, 2 policy settings and 1 grant'
+func (deleted *DeletedResources) Summary() string {
+	return fmt.Sprintf("%s, %s and %s",
+		pluralise(len(deleted.Resources), "resource"),
+		pluralise(len(deleted.PolicySettings), "policy setting"),
+		pluralise(len(deleted.Grants), "grant"))
+}
+
+func pluralise(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
+
 func (client *Client) ResourceExists(id string) (bool, error) {
 	resource, err := client.ReadResource(id, nil)
 
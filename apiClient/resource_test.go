@@ -0,0 +1,40 @@
+package apiClient
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSortDeepestFirst(t *testing.T) {
+	resources := []Resource{
+		{Turbot: TurbotResourceMetadata{Id: "child", Path: "1.2.3"}},
+		{Turbot: TurbotResourceMetadata{Id: "grandchild", Path: "1.2.3.4"}},
+		{Turbot: TurbotResourceMetadata{Id: "other child", Path: "1.2.5"}},
+		{Turbot: TurbotResourceMetadata{Id: "great grandchild", Path: "1.2.3.4.6"}},
+	}
+	sortDeepestFirst(resources)
+	var ids []string
+	for _, resource := range resources {
+		ids = append(ids, resource.Turbot.Id)
+	}
+	// resources at the same depth keep their order
+	assert.Equal(t, []string{"great grandchild", "grandchild", "child", "other child"}, ids)
+}
+
+func TestDeletedResourcesReport(t *testing.T) {
+	deleted := &DeletedResources{
+		Resources: []TurbotResourceMetadata{
+			{Id: "3", Title: "child"},
+			{Id: "2", Title: "parent"},
+		},
+		PolicySettings: []PolicySetting{
+			{Type: PolicyType{Uri: "tmod:@turbot/turbot#/policy/types/regions"}, Turbot: TurbotPolicyMetadata{Id: "10", ResourceId: "3"}},
+		},
+	}
+	assert.Equal(t, "2 resources, 1 policy setting and 0 grants", deleted.Summary())
+	assert.Equal(t, []string{
+		"policy setting 10 (tmod:@turbot/turbot#/policy/types/regions) on resource 3",
+		"resource 3 (child)",
+		"resource 2 (parent)",
+	}, deleted.Descriptions())
+}
@@ -5,7 +5,7 @@ import (
 )
 
 func (client *Client) CreateSmartFolder(input map[string]interface{}) (*SmartFolder, error) {
-	query := createSmartFolderMutation()
+	query := createSmartFolderMutation(client.supportsSmartFolderFilters())
 	responseData := &SmartFolderResponse{}
 	variables := map[string]interface{}{
 		"input": input,
@@ -30,7 +30,7 @@ func (client *Client) ReadSmartFolder(id string) (*SmartFolder, error) {
 }
 
 func (client *Client) UpdateSmartFolder(input map[string]interface{}) (*SmartFolder, error) {
-	query := updateSmartFolderMutation()
+	query := updateSmartFolderMutation(client.supportsSmartFolderFilters())
 	responseData := &SmartFolderResponse{}
 	variables := map[string]interface{}{
 		"input": input,
@@ -32,3 +32,96 @@ func (client *Client) DeleteSmartFolderAttachment(input map[string]interface{})
 	}
 	return nil
 }
+
+// the maximum number of attachments made in a single request
+const smartFolderAttachmentBatchSize = 50
+
+// attach a list of resources (ids or akas) to a smart folder, batching the attachments into as few requests as possible
+func (client *Client) AttachSmartFolderResources(smartFolderId string, resources []string) error {
+	if err := client.batchSmartFolderAttachments(false, smartFolderId, resources); err != nil {
+		return fmt.Errorf("error creating smart folder attachments: %s", err.Error())
+	}
+	return nil
+}
+
+// detach a list of resources (ids or akas) from a smart folder, batching the detachments into as few requests as possible
+func (client *Client) DetachSmartFolderResources(smartFolderId string, resources []string) error {
+	if err := client.batchSmartFolderAttachments(true, smartFolderId, resources); err != nil {
+		return fmt.Errorf("error deleting smart folder attachments: %s", err.Error())
+	}
+	return nil
+}
+
+func (client *Client) batchSmartFolderAttachments(detach bool, smartFolderId string, resources []string) error {
+	for start := 0; start < len(resources); start += smartFolderAttachmentBatchSize {
+		end := start + smartFolderAttachmentBatchSize
+		if end > len(resources) {
+			end = len(resources)
+		}
+		batch := resources[start:end]
+
+		query := batchSmartFolderAttachmentMutation(detach, len(batch))
+		var responseData interface{}
+		variables := map[string]interface{}{}
+		for i, resource := range batch {
+			variables[fmt.Sprintf("input%d", i)] = map[string]interface{}{
+				"resource":     resource,
+				"smartFolders": []string{smartFolderId},
+			}
+		}
+		// execute api call
+		if err := client.doRequest(query, variables, &responseData); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// read the metadata of all resources attached to a smart folder, reading all pages of results
+func (client *Client) ReadSmartFolderAttachedResources(smartFolderId string) ([]TurbotResourceMetadata, error) {
+	var attachedResources []TurbotResourceMetadata
+	paging := ""
+	for {
+		query := readSmartFolderAttachedResourcesQuery(smartFolderId, paging)
+		responseData := &SmartFolderAttachedResourcesResponse{}
+
+		// execute api call
+		if err := client.doRequest(query, nil, responseData); err != nil {
+			return nil, fmt.Errorf("error reading smart folder attached resources: %s", err.Error())
+		}
+		for _, item := range responseData.SmartFolder.AttachedResources.Items {
+			attachedResources = append(attachedResources, item.Turbot)
+		}
+		paging = responseData.SmartFolder.AttachedResources.Paging.Next
+		if paging == "" {
+			return attachedResources, nil
+		}
+	}
+}
+
+// is the resource (specified by id or aka) attached to the smart folder?
+func (client *Client) SmartFolderAttachmentExists(smartFolderId, resourceAka string) (bool, error) {
+	attachedResources, err := client.ReadSmartFolderAttachedResources(smartFolderId)
+	if err != nil {
+		return false, err
+	}
+	for _, attachedResource := range attachedResources {
+		if ResourceMatches(attachedResource, resourceAka) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// does the resource metadata have the given id or aka?
+func ResourceMatches(resource TurbotResourceMetadata, resourceAka string) bool {
+	if resource.Id == resourceAka {
+		return true
+	}
+	for _, aka := range resource.Akas {
+		if aka == resourceAka {
+			return true
+		}
+	}
+	return false
+}
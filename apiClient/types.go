@@ -25,11 +25,25 @@ type ReadResourceListResponse struct {
 	}
 }
 
+type ReadResourceListPageResponse struct {
+	ResourceList struct {
+		Items  []Resource
+		Paging Paging
+	}
+}
+
 type Resource struct {
 	Turbot TurbotResourceMetadata
 	Data   map[string]interface{}
 }
 
+// DeletedResources records the objects removed by DeleteResourceTree
+type DeletedResources struct {
+	Resources      []TurbotResourceMetadata
+	PolicySettings []PolicySetting
+	Grants         []Grant
+}
+
 type ReadSerializableResourceResponse struct {
 	Resource struct {
 		Data   map[string]interface{}
@@ -49,16 +63,59 @@ type SerializableResource struct {
 
 // Validation response
 type ValidationResponse struct {
-	Schema struct {
-		QueryType struct {
-			Name string
-		}
+	Actor struct {
+		Identity CallerIdentityResource
 	}
 }
 
 // is the validation response successful?
 func (response *ValidationResponse) isValid() bool {
-	return response.Schema.QueryType.Name == "Query"
+	return response.Actor.Identity.Turbot.Id != ""
+}
+
+// Capabilities
+type CapabilitiesResponse struct {
+	TurbotMod struct {
+		Version string
+	}
+	SmartFolderType struct {
+		Fields []struct {
+			Name string
+		}
+	}
+	GrantType struct {
+		Fields []struct {
+			Name string
+		}
+	}
+}
+
+// Caller identity
+type CallerIdentityResource struct {
+	ProfileId string
+	Email     string
+	Status    string
+	Turbot    TurbotResourceMetadata
+}
+
+type CallerIdentity struct {
+	Id        string
+	ProfileId string
+	Email     string
+	Status    string
+	Title     string
+	Akas      []string
+	Directory string
+	Grants    []Grant
+}
+
+type ReadActiveGrantsResponse struct {
+	ActiveGrants struct {
+		Items []struct {
+			Grant Grant
+		}
+		Paging Paging
+	}
 }
 
 // ApiResponse
@@ -82,6 +139,13 @@ type FindPolicySettingResponse struct {
 	}
 }
 
+type PolicySettingListResponse struct {
+	PolicySettings struct {
+		Items  []PolicySetting
+		Paging Paging
+	}
+}
+
 type PolicySetting struct {
 	Value              interface{}
 	ValueSource        string
@@ -93,9 +157,15 @@ type PolicySetting struct {
 	Note               string
 	ValidFromTimestamp string
 	ValidToTimestamp   string
+	Type               PolicyType
 	Turbot             TurbotPolicyMetadata
 }
 
+type PolicyType struct {
+	Uri    string
+	Secret bool
+}
+
 // PolicyValue
 type PolicyValueResponse struct {
 	PolicyValue PolicyValue
@@ -136,6 +206,31 @@ type ModVersionResponse struct {
 	}
 }
 
+type ModVersionResourceTypesResponse struct {
+	ModVersion struct {
+		ResourceTypes []struct {
+			Uri string
+		}
+	}
+}
+
+type ResourceTypeListResponse struct {
+	ResourceTypes struct {
+		Items []struct {
+			Uri string
+		}
+		Paging Paging
+	}
+}
+
+// ModUninstallBlockers records what would be broken by uninstalling a mod
+type ModUninstallBlockers struct {
+	// uris of the installed mods which depend on the mod
+	DependentMods []string
+	// uris of the mod's resource types which have resources
+	ResourceTypesInUse []string
+}
+
 type UninstallModResponse struct {
 	UninstallMod struct {
 		Success bool
@@ -161,10 +256,19 @@ type ReadGrantResponse struct {
 	Grant Grant
 }
 
+type ReadGrantsResponse struct {
+	Grants struct {
+		Items  []Grant
+		Paging Paging
+	}
+}
+
 type Grant struct {
-	Turbot            TurbotGrantMetadata
-	PermissionTypeId  string
-	PermissionLevelId string
+	Turbot             TurbotGrantMetadata
+	PermissionTypeId   string
+	PermissionLevelId  string
+	ValidFromTimestamp string
+	ValidToTimestamp   string
 }
 
 // Active Grant
@@ -231,6 +335,23 @@ type SmartFolder struct {
 	}
 }
 
+type SmartFolderAttachedResourcesResponse struct {
+	SmartFolder struct {
+		AttachedResources struct {
+			Items []struct {
+				Turbot TurbotResourceMetadata
+			}
+			Paging Paging
+		}
+	}
+}
+
+// Paging
+// the 'next' token is passed as the paging argument to fetch the next page - it is empty for the last page
+type Paging struct {
+	Next string
+}
+
 // Smart folder attachment
 type SmartFolderAttachment struct {
 	Turbot      TurbotResourceMetadata
@@ -346,6 +467,8 @@ type TurbotPolicyMetadata struct {
 	ParentId   string
 	ResourceId string
 	Akas       []string
+	VersionId  string
+	Terraform  map[string]interface{}
 }
 
 type TurbotGrantMetadata struct {
@@ -0,0 +1,63 @@
+// turbot-tfgen generates Terraform configuration for the existing resources in a Turbot subtree,
+// along with the terraform import commands to bring them under Terraform management
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/terraform-providers/terraform-provider-turbot/apiClient"
+	"github.com/terraform-providers/terraform-provider-turbot/tfgen"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	root := flag.String("root", "tmod:@turbot/turbot#/", "id or aka of the resource at the top of the subtree to generate")
+	profile := flag.String("profile", "", "credentials file profile to use")
+	credentialsFile := flag.String("credentials-file", "", "path of the credentials file")
+	outputDir := flag.String("out", ".", "directory to write turbot.tf and import.sh to")
+	verbose := flag.Bool("verbose", false, "log each API request")
+	flag.Parse()
+
+	if !*verbose {
+		log.SetOutput(ioutil.Discard)
+	}
+	if err := run(*root, *outputDir, apiClient.ClientConfig{Profile: *profile, CredentialsPath: *credentialsFile}); err != nil {
+		fmt.Fprintf(os.Stderr, "turbot-tfgen: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
+
+func run(root, outputDir string, config apiClient.ClientConfig) error {
+	client, err := apiClient.CreateClient(config)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %s", err.Error())
+	}
+	if err = client.Validate(); err != nil {
+		return fmt.Errorf("failed to validate client: %s", err.Error())
+	}
+
+	generator := tfgen.NewGenerator(client)
+	blocks, err := generator.Generate(root)
+	if err != nil {
+		return err
+	}
+
+	configPath := filepath.Join(outputDir, "turbot.tf")
+	if err := ioutil.WriteFile(configPath, []byte(tfgen.WriteConfig(blocks)), 0644); err != nil {
+		return err
+	}
+	importPath := filepath.Join(outputDir, "import.sh")
+	importScript := "#!/bin/sh\nset -e\n\n" + tfgen.WriteImportCommands(blocks)
+	if err := ioutil.WriteFile(importPath, []byte(importScript), 0755); err != nil {
+		return err
+	}
+	fmt.Printf("generated %d resources\n  configuration: %s\n  import commands: %s\n", len(blocks), configPath, importPath)
+	fmt.Println("NOTE: the configuration may contain policy setting values - review it before committing it to source control")
+	for _, warning := range generator.Warnings {
+		fmt.Fprintf(os.Stderr, "WARNING: %s\n", warning)
+	}
+	return nil
+}
@@ -63,3 +63,28 @@ func TestRemoveProperties(t *testing.T) {
 		assert.Equal(t, test.expected, result)
 	}
 }
+
+func TestYamlStringToMap(t *testing.T) {
+	data, err := YamlStringToMap(`
+title: provider_test
+count: 2
+nested:
+  a: b
+  list:
+  - c: d
+`)
+	assert.Nil(t, err)
+	expected := map[string]interface{}{
+		"title":  "provider_test",
+		"count":  2,
+		"nested": map[string]interface{}{"a": "b", "list": []interface{}{map[string]interface{}{"c": "d"}}},
+	}
+	assert.Equal(t, expected, data)
+
+	// the result can be converted to json
+	_, err = MapToJsonString(data)
+	assert.Nil(t, err)
+
+	_, err = YamlStringToMap("title: [")
+	assert.NotNil(t, err)
+}
@@ -2,6 +2,8 @@ package helpers
 
 import (
 	"encoding/json"
+	"fmt"
+	"github.com/go-yaml/yaml"
 	"github.com/hashicorp/terraform/helper/encryption"
 	"reflect"
 )
@@ -79,6 +81,44 @@ func JsonStringToMap(dataString string) (map[string]interface{}, error) {
 	return data, nil
 }
 
+// unmarshal a yaml string into a map, converting any nested yaml maps to map[string]interface{} so the result can be json encoded
+func YamlStringToMap(dataString string) (map[string]interface{}, error) {
+	var data = make(map[string]interface{})
+	if err := yaml.Unmarshal([]byte(dataString), &data); err != nil {
+		return nil, err
+	}
+	for k, v := range data {
+		data[k] = convertYamlValue(v)
+	}
+	return data, nil
+}
+
+func MapToYamlString(data map[string]interface{}) (string, error) {
+	dataBytes, err := yaml.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	return string(dataBytes), nil
+}
+
+// recursively convert map[interface{}]interface{} (as returned by the yaml parser) to map[string]interface{}
+func convertYamlValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		var result = make(map[string]interface{})
+		for key, item := range v {
+			result[fmt.Sprintf("%v", key)] = convertYamlValue(item)
+		}
+		return result
+	case []interface{}:
+		for i, item := range v {
+			v[i] = convertYamlValue(item)
+		}
+		return v
+	}
+	return value
+}
+
 // apply standard formatting to a json string by unmarshalling into a map then marshalling back to JSON
 func FormatJson(body string) string {
 	data := map[string]interface{}{}
@@ -0,0 +1,394 @@
+package tfgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/iancoleman/strcase"
+	"github.com/terraform-providers/terraform-provider-turbot/apiClient"
+	"github.com/terraform-providers/terraform-provider-turbot/turbot"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// a Turbot resource type which is converted to a Terraform resource
+type resourceType struct {
+	terraformType string
+	typeUri       string
+	// turbot property names which are not the lowerCamelCase of the terraform property name
+	propertyNames map[string]string
+}
+
+// the Turbot resource types which are converted to Terraform resources
+var generatedResourceTypes = []resourceType{
+	{terraformType: "turbot_mod", typeUri: "tmod:@turbot/turbot#/resource/types/mod"},
+	{terraformType: "turbot_folder", typeUri: "tmod:@turbot/turbot#/resource/types/folder"},
+	{terraformType: "turbot_smart_folder", typeUri: "tmod:@turbot/turbot#/resource/types/smartFolder"},
+	{terraformType: "turbot_local_directory", typeUri: "tmod:@turbot/turbot-iam#/resource/types/localDirectory"},
+	{terraformType: "turbot_saml_directory", typeUri: "tmod:@turbot/turbot-iam#/resource/types/samlDirectory"},
+	{terraformType: "turbot_google_directory", typeUri: "tmod:@turbot/turbot-iam#/resource/types/googleDirectory", propertyNames: map[string]string{"client_id": "clientID"}},
+}
+
+// attributes which are set explicitly rather than from the resource data
+var explicitAttributes = []string{"parent", "tags", "policy"}
+
+// Generator walks a resource subtree and builds the Terraform configuration for the resources it finds
+type Generator struct {
+	client *apiClient.Client
+	// the provider resource schemas - these determine which attributes are generated
+	schemas map[string]*schema.Resource
+	rootAka string
+	rootId  string
+	blocks  []*Block
+	// the block generated for each resource, keyed by turbot id
+	resourceBlocks map[string]*Block
+	names          map[string]bool
+	// Warnings lists the generated configuration which must be completed manually
+	Warnings []string
+}
+
+// a resource found in the subtree
+type subtreeResource struct {
+	resourceType resourceType
+	resource     apiClient.Resource
+}
+
+func NewGenerator(client *apiClient.Client) *Generator {
+	return &Generator{
+		client:         client,
+		schemas:        turbot.Provider().(*schema.Provider).ResourcesMap,
+		resourceBlocks: make(map[string]*Block),
+		names:          make(map[string]bool),
+	}
+}
+
+// Generate builds the Terraform configuration for the mods, folders, smart folders and attachments, directories,
+// policy settings and grants in the subtree of the given resource
+func (g *Generator) Generate(rootAka string) ([]*Block, error) {
+	root, err := g.client.ReadResource(rootAka, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error reading root resource %s: %s", rootAka, err.Error())
+	}
+	g.rootAka = rootAka
+	g.rootId = root.Turbot.Id
+	subtreeFilter := fmt.Sprintf("resource:%s level:self,descendant", g.rootId)
+
+	resources, err := g.readSubtreeResources(subtreeFilter)
+	if err != nil {
+		return nil, err
+	}
+	// create the blocks first, so the resources can refer to each other regardless of order
+	for _, r := range resources {
+		block := g.addBlock(r.resourceType.terraformType, resourceTitle(r.resource), r.resource.Turbot.Id)
+		g.resourceBlocks[r.resource.Turbot.Id] = block
+	}
+	for _, r := range resources {
+		if err := g.populateResourceBlock(r); err != nil {
+			return nil, err
+		}
+	}
+	if err := g.generatePolicySettings(subtreeFilter); err != nil {
+		return nil, err
+	}
+	if err := g.generateGrants(subtreeFilter); err != nil {
+		return nil, err
+	}
+	return g.blocks, nil
+}
+
+// read the resources of each generated type, ordered so parents are before their descendants
+func (g *Generator) readSubtreeResources(subtreeFilter string) ([]subtreeResource, error) {
+	var resources []subtreeResource
+	for _, t := range generatedResourceTypes {
+		filter := fmt.Sprintf("resourceTypeId:'%s' %s", t.typeUri, subtreeFilter)
+		// an empty property path reads the full resource data
+		items, err := g.client.ReadResourceListAll(filter, map[string]string{"data": ""})
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			resources = append(resources, subtreeResource{t, item})
+		}
+	}
+	sort.SliceStable(resources, func(i, j int) bool {
+		return len(strings.Split(resources[i].resource.Turbot.Path, ".")) < len(strings.Split(resources[j].resource.Turbot.Path, "."))
+	})
+	return resources, nil
+}
+
+// add a block with a unique name
+func (g *Generator) addBlock(terraformType, title, importId string) *Block {
+	name := resourceName(title)
+	if g.names[terraformType+"."+name] {
+		name = fmt.Sprintf("%s_%s", name, importId)
+	}
+	g.names[terraformType+"."+name] = true
+	block := &Block{Type: terraformType, Name: name, ImportId: importId}
+	g.blocks = append(g.blocks, block)
+	return block
+}
+
+func (g *Generator) populateResourceBlock(r subtreeResource) error {
+	block := g.resourceBlocks[r.resource.Turbot.Id]
+	block.Attributes = append(block.Attributes, Attribute{"parent", g.resourceReference(r.resource.Turbot.ParentId)})
+
+	if r.resourceType.terraformType == "turbot_mod" {
+		return g.populateModBlock(block)
+	}
+
+	resourceSchema := g.schemas[r.resourceType.terraformType].Schema
+	for _, name := range sortedAttributeNames(resourceSchema) {
+		attributeSchema := resourceSchema[name]
+		if isExplicitAttribute(name) || !isConfigurable(attributeSchema) {
+			continue
+		}
+		if attributeSchema.Sensitive {
+			block.Comments = append(block.Comments, fmt.Sprintf("%s is sensitive and must be added manually", name))
+			continue
+		}
+		propertyName, ok := r.resourceType.propertyNames[name]
+		if !ok {
+			propertyName = strcase.ToLowerCamel(name)
+		}
+		value, err := attributeValue(attributeSchema, r.resource.Data[propertyName])
+		if err != nil {
+			return fmt.Errorf("error generating %s for %s: %s", name, block.Address(), err.Error())
+		}
+		if value != nil {
+			block.Attributes = append(block.Attributes, Attribute{name, value})
+		}
+	}
+	if _, ok := resourceSchema["tags"]; ok && len(r.resource.Turbot.Tags) > 0 {
+		block.Attributes = append(block.Attributes, Attribute{"tags", r.resource.Turbot.Tags})
+	}
+
+	if r.resourceType.terraformType == "turbot_smart_folder" {
+		return g.populateSmartFolder(block, r.resource.Turbot.Id)
+	}
+	return nil
+}
+
+func (g *Generator) populateModBlock(block *Block) error {
+	mod, err := g.client.ReadMod(block.ImportId)
+	if err != nil {
+		return err
+	}
+	block.Attributes = append(block.Attributes,
+		Attribute{"org", mod.Org},
+		Attribute{"mod", mod.Mod},
+		Attribute{"version", mod.Version})
+	return nil
+}
+
+// add the smart folder policy settings as policy blocks, and generate a turbot_smart_folder_attachments resource
+func (g *Generator) populateSmartFolder(block *Block, smartFolderId string) error {
+	settings, err := g.client.ReadResourcePolicySettings(smartFolderId)
+	if err != nil {
+		return err
+	}
+	var policies []NestedBlock
+	for _, setting := range settings {
+		// NOTE: smart folder policy blocks do not support template_input
+		var attributes []Attribute
+		for _, attribute := range g.policySettingAttributes(setting, block.Address()) {
+			if attribute.Name != "template_input" {
+				attributes = append(attributes, attribute)
+			}
+		}
+		policies = append(policies, NestedBlock{Attributes: attributes})
+	}
+	if len(policies) > 0 {
+		block.Attributes = append(block.Attributes, Attribute{"policy", policies})
+	}
+
+	attachedResources, err := g.client.ReadSmartFolderAttachedResources(smartFolderId)
+	if err != nil {
+		return err
+	}
+	if len(attachedResources) == 0 {
+		return nil
+	}
+	var resources []interface{}
+	for _, attachedResource := range attachedResources {
+		resources = append(resources, g.resourceReference(attachedResource.Id))
+	}
+	attachments := g.addBlock("turbot_smart_folder_attachments", block.Name, smartFolderId)
+	attachments.Attributes = []Attribute{
+		{"smart_folder", g.resourceReference(smartFolderId)},
+		{"resources", resources},
+	}
+	return nil
+}
+
+// generate a turbot_policy_setting for each setting in the subtree
+// NOTE: settings on smart folders are generated as policy blocks of the smart folder
+func (g *Generator) generatePolicySettings(subtreeFilter string) error {
+	settings, err := g.client.ReadPolicySettingList(subtreeFilter)
+	if err != nil {
+		return err
+	}
+	for _, setting := range settings {
+		if resourceBlock, ok := g.resourceBlocks[setting.Turbot.ResourceId]; ok && resourceBlock.Type == "turbot_smart_folder" {
+			continue
+		}
+		title := fmt.Sprintf("%s_%s", g.resourceTitle(setting.Turbot.ResourceId), policyTypeName(setting.Type.Uri))
+		block := g.addBlock("turbot_policy_setting", title, setting.Turbot.Id)
+		block.Attributes = append([]Attribute{{"resource", g.resourceReference(setting.Turbot.ResourceId)}}, g.policySettingAttributes(setting, block.Address())...)
+		if setting.ValidFromTimestamp != "" {
+			block.Attributes = append(block.Attributes, Attribute{"valid_from_timestamp", setting.ValidFromTimestamp})
+		}
+		if setting.ValidToTimestamp != "" {
+			block.Attributes = append(block.Attributes, Attribute{"valid_to_timestamp", setting.ValidToTimestamp})
+		}
+	}
+	return nil
+}
+
+// generate a turbot_grant for each grant in the subtree
+func (g *Generator) generateGrants(subtreeFilter string) error {
+	grants, err := g.client.ReadGrantList(subtreeFilter)
+	if err != nil {
+		return err
+	}
+	for _, grant := range grants {
+		title := fmt.Sprintf("%s_grant", g.resourceTitle(grant.Turbot.ResourceId))
+		block := g.addBlock("turbot_grant", title, grant.ImportId())
+		block.Attributes = []Attribute{
+			{"resource", g.resourceReference(grant.Turbot.ResourceId)},
+			{"identity", grant.Turbot.ProfileId},
+			{"type", grant.PermissionTypeId},
+			{"level", grant.PermissionLevelId},
+		}
+		// only set if the workspace supports grant validity periods
+		if grant.ValidToTimestamp != "" {
+			block.Attributes = append(block.Attributes, Attribute{"valid_to", grant.ValidToTimestamp})
+		}
+	}
+	return nil
+}
+
+// the attributes shared by turbot_policy_setting and smart folder policy blocks
+// NOTE: secret values are not written - the value is commented out and a warning is added
+func (g *Generator) policySettingAttributes(setting apiClient.PolicySetting, address string) []Attribute {
+	attributes := []Attribute{{"type", setting.Type.Uri}}
+	if setting.Type.Secret {
+		attributes = append(attributes, Attribute{"value", Commented(`"<secret>"`)})
+		g.Warnings = append(g.Warnings, fmt.Sprintf("%s: the value of secret policy %s is not generated and must be added manually", address, setting.Type.Uri))
+	} else if setting.Template != "" {
+		attributes = append(attributes, Attribute{"template", setting.Template})
+		if setting.TemplateInput != "" {
+			attributes = append(attributes, Attribute{"template_input", setting.TemplateInput})
+		}
+	} else {
+		// use the value source, as this is the YAML representation of the value
+		value := setting.ValueSource
+		if value == "" {
+			value = fmt.Sprintf("%v", setting.Value)
+		}
+		attributes = append(attributes, Attribute{"value", value})
+	}
+	if setting.Precedence != "" && setting.Precedence != "REQUIRED" {
+		attributes = append(attributes, Attribute{"precedence", setting.Precedence})
+	}
+	if setting.Note != "" {
+		attributes = append(attributes, Attribute{"note", setting.Note})
+	}
+	return attributes
+}
+
+// refer to a generated resource by its address, the root by the aka it was specified with, and anything else by id
+func (g *Generator) resourceReference(id string) interface{} {
+	if block, ok := g.resourceBlocks[id]; ok {
+		return Reference(block.Address() + ".id")
+	}
+	if id == g.rootId {
+		return g.rootAka
+	}
+	return id
+}
+
+// the title used to name resources relating to the given resource
+func (g *Generator) resourceTitle(id string) string {
+	if block, ok := g.resourceBlocks[id]; ok {
+		return block.Name
+	}
+	if id == g.rootId {
+		return "root"
+	}
+	return id
+}
+
+// use the title of the resource, falling back to the last segment of its first aka, then its id
+func resourceTitle(resource apiClient.Resource) string {
+	if title, ok := resource.Data["title"].(string); ok && title != "" {
+		return title
+	}
+	if len(resource.Turbot.Akas) > 0 {
+		segments := strings.Split(resource.Turbot.Akas[0], "/")
+		return segments[len(segments)-1]
+	}
+	return resource.Turbot.Id
+}
+
+// e.g. tmod:@turbot/aws#/policy/types/regionsDefault -> aws_regions_default
+func policyTypeName(policyTypeUri string) string {
+	uri := strings.TrimPrefix(policyTypeUri, "tmod:@")
+	parts := strings.SplitN(uri, "#/policy/types/", 2)
+	if len(parts) != 2 {
+		return uri
+	}
+	mod := strings.Replace(parts[0][strings.Index(parts[0], "/")+1:], "-", "_", -1)
+	return fmt.Sprintf("%s_%s", mod, strcase.ToSnake(parts[1]))
+}
+
+// convert a value from the resource data to the form required by the attribute schema
+// returns nil if the attribute should not be generated
+func attributeValue(attributeSchema *schema.Schema, value interface{}) (interface{}, error) {
+	if value == nil || reflect.DeepEqual(value, attributeSchema.Default) {
+		return nil, nil
+	}
+	switch attributeSchema.Type {
+	case schema.TypeString:
+		if s, ok := value.(string); ok {
+			if s == "" {
+				return nil, nil
+			}
+			return s, nil
+		}
+		// complex values are passed as JSON strings
+		jsonBytes, err := json.MarshalIndent(value, "", " ")
+		if err != nil {
+			return nil, err
+		}
+		return string(jsonBytes), nil
+	case schema.TypeList, schema.TypeSet:
+		if items, ok := value.([]interface{}); ok && len(items) > 0 {
+			return items, nil
+		}
+		return nil, nil
+	}
+	return value, nil
+}
+
+func isConfigurable(attributeSchema *schema.Schema) bool {
+	return (attributeSchema.Required || attributeSchema.Optional) && attributeSchema.Deprecated == ""
+}
+
+func isExplicitAttribute(name string) bool {
+	for _, explicit := range explicitAttributes {
+		if name == explicit {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedAttributeNames(resourceSchema map[string]*schema.Schema) []string {
+	var names []string
+	for name := range resourceSchema {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,65 @@
+package tfgen
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/terraform-providers/terraform-provider-turbot/apiClient"
+	"testing"
+)
+
+func TestPolicyTypeName(t *testing.T) {
+	assert.Equal(t, "aws_regions_default", policyTypeName("tmod:@turbot/aws#/policy/types/regionsDefault"))
+	assert.Equal(t, "aws_s3_bucket_versioning", policyTypeName("tmod:@turbot/aws-s3#/policy/types/bucketVersioning"))
+	assert.Equal(t, "custom", policyTypeName("custom"))
+}
+
+func TestResourceTitle(t *testing.T) {
+	resource := apiClient.Resource{Data: map[string]interface{}{"title": "Production"}}
+	assert.Equal(t, "Production", resourceTitle(resource))
+
+	resource = apiClient.Resource{Turbot: apiClient.TurbotResourceMetadata{Id: "123", Akas: []string{"tmod:@turbot/aws"}}}
+	assert.Equal(t, "aws", resourceTitle(resource))
+
+	resource = apiClient.Resource{Turbot: apiClient.TurbotResourceMetadata{Id: "123"}}
+	assert.Equal(t, "123", resourceTitle(resource))
+}
+
+func TestAttributeValue(t *testing.T) {
+	type test struct {
+		name     string
+		schema   *schema.Schema
+		value    interface{}
+		expected interface{}
+	}
+	tests := []test{
+		{"String", &schema.Schema{Type: schema.TypeString}, "a", "a"},
+		{"Empty string", &schema.Schema{Type: schema.TypeString}, "", nil},
+		{"Missing", &schema.Schema{Type: schema.TypeString}, nil, nil},
+		{"Default", &schema.Schema{Type: schema.TypeString, Default: "Active"}, "Active", nil},
+		{"Complex string", &schema.Schema{Type: schema.TypeString}, map[string]interface{}{"a": "b"}, "{\n \"a\": \"b\"\n}"},
+		{"List", &schema.Schema{Type: schema.TypeList}, []interface{}{"a"}, []interface{}{"a"}},
+		{"Empty list", &schema.Schema{Type: schema.TypeList}, []interface{}{}, nil},
+		{"Bool", &schema.Schema{Type: schema.TypeBool}, true, true},
+	}
+	for _, test := range tests {
+		value, err := attributeValue(test.schema, test.value)
+		assert.Nil(t, err, test.name)
+		assert.Equal(t, test.expected, value, test.name)
+	}
+}
+
+func TestPolicySettingAttributes(t *testing.T) {
+	g := NewGenerator(nil)
+	setting := apiClient.PolicySetting{Type: apiClient.PolicyType{Uri: "tmod:@turbot/aws#/policy/types/regionsDefault"}, ValueSource: "- eu-west-1\n"}
+	attributes := g.policySettingAttributes(setting, "turbot_policy_setting.regions")
+	assert.Equal(t, []Attribute{{"type", setting.Type.Uri}, {"value", "- eu-west-1\n"}}, attributes)
+	assert.Empty(t, g.Warnings)
+
+	// secret values are commented out, with a warning
+	setting = apiClient.PolicySetting{Type: apiClient.PolicyType{Uri: "tmod:@turbot/turbot#/policy/types/secret", Secret: true}, ValueSource: "password"}
+	attributes = g.policySettingAttributes(setting, "turbot_policy_setting.secret")
+	assert.Equal(t, []Attribute{{"type", setting.Type.Uri}, {"value", Commented(`"<secret>"`)}}, attributes)
+	if assert.Len(t, g.Warnings, 1) {
+		assert.Contains(t, g.Warnings[0], "turbot_policy_setting.secret")
+	}
+}
@@ -0,0 +1,204 @@
+package tfgen
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Block is a generated Terraform resource, along with the id used to import it
+type Block struct {
+	Type     string
+	Name     string
+	ImportId string
+	// comments written before the block, e.g. for attributes which must be set manually
+	Comments   []string
+	Attributes []Attribute
+}
+
+// Address returns the Terraform address of the resource
+func (block *Block) Address() string {
+	return fmt.Sprintf("%s.%s", block.Type, block.Name)
+}
+
+// Attribute is a single attribute of a block
+// the value may be a string, bool, number, list, map, Reference or []NestedBlock
+type Attribute struct {
+	Name  string
+	Value interface{}
+}
+
+// Reference is an expression referring to another resource, e.g. turbot_folder.prod.id - it is written unquoted
+type Reference string
+
+// Commented is the value of an attribute which is written commented out, e.g. a secret which must be set manually
+type Commented string
+
+// NestedBlock is a nested configuration block, e.g. a smart folder policy
+type NestedBlock struct {
+	Attributes []Attribute
+}
+
+var invalidNameCharsRegex = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// convert a title into a valid Terraform resource name
+func resourceName(title string) string {
+	name := invalidNameCharsRegex.ReplaceAllString(strings.ToLower(title), "_")
+	name = strings.Trim(name, "_")
+	// names must start with a letter or underscore
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+// WriteConfig writes the HCL for the blocks
+func WriteConfig(blocks []*Block) string {
+	var buffer bytes.Buffer
+	for i, block := range blocks {
+		if i > 0 {
+			buffer.WriteString("\n")
+		}
+		for _, comment := range block.Comments {
+			buffer.WriteString(fmt.Sprintf("# %s\n", comment))
+		}
+		buffer.WriteString(fmt.Sprintf("resource %q %q {\n", block.Type, block.Name))
+		writeAttributes(&buffer, block.Attributes, 1)
+		buffer.WriteString("}\n")
+	}
+	return buffer.String()
+}
+
+// WriteImportCommands writes a terraform import command for each block
+func WriteImportCommands(blocks []*Block) string {
+	var buffer bytes.Buffer
+	for _, block := range blocks {
+		buffer.WriteString(fmt.Sprintf("terraform import %s %s\n", block.Address(), block.ImportId))
+	}
+	return buffer.String()
+}
+
+// write attributes, aligning the '=' of consecutive simple attributes as terraform fmt does (commented out attributes are not aligned)
+func writeAttributes(buffer *bytes.Buffer, attributes []Attribute, depth int) {
+	indent := strings.Repeat("  ", depth)
+	width := 0
+	for _, attribute := range attributes {
+		switch attribute.Value.(type) {
+		case []NestedBlock, Commented:
+			continue
+		}
+		if len(attribute.Name) > width {
+			width = len(attribute.Name)
+		}
+	}
+	for _, attribute := range attributes {
+		if nestedBlocks, ok := attribute.Value.([]NestedBlock); ok {
+			for _, nestedBlock := range nestedBlocks {
+				buffer.WriteString(fmt.Sprintf("\n%s%s {\n", indent, attribute.Name))
+				writeAttributes(buffer, nestedBlock.Attributes, depth+1)
+				buffer.WriteString(fmt.Sprintf("%s}\n", indent))
+			}
+			continue
+		}
+		if commented, ok := attribute.Value.(Commented); ok {
+			buffer.WriteString(fmt.Sprintf("%s# %s = %s\n", indent, attribute.Name, commented))
+			continue
+		}
+		buffer.WriteString(fmt.Sprintf("%s%-*s = %s\n", indent, width, attribute.Name, formatValue(attribute.Value, depth)))
+	}
+}
+
+func formatValue(value interface{}, depth int) string {
+	switch v := value.(type) {
+	case Reference:
+		return string(v)
+	case string:
+		return formatString(v)
+	case []string:
+		var items []interface{}
+		for _, item := range v {
+			items = append(items, item)
+		}
+		return formatList(items, depth)
+	case []interface{}:
+		return formatList(v, depth)
+	case map[string]string:
+		var items = map[string]interface{}{}
+		for key, item := range v {
+			items[key] = item
+		}
+		return formatMap(items, depth)
+	case map[string]interface{}:
+		return formatMap(v, depth)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// strings containing newlines are written as heredocs
+// NOTE: template sequences are escaped so the value is not interpolated
+func formatString(value string) string {
+	value = strings.Replace(value, "${", "$${", -1)
+	value = strings.Replace(value, "%{", "%%{", -1)
+	// (unless the value contains the heredoc delimiter)
+	multiline := strings.Contains(strings.TrimSuffix(value, "\n"), "\n")
+	if multiline && !strings.HasPrefix(value, "EOT\n") && !strings.Contains(value, "\nEOT\n") {
+		if !strings.HasSuffix(value, "\n") {
+			value += "\n"
+		}
+		return fmt.Sprintf("<<EOT\n%sEOT", value)
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return fmt.Sprintf(`"%s"`, replacer.Replace(value))
+}
+
+func formatList(items []interface{}, depth int) string {
+	if len(items) == 0 {
+		return "[]"
+	}
+	if len(items) == 1 {
+		return fmt.Sprintf("[%s]", formatValue(items[0], depth))
+	}
+	indent := strings.Repeat("  ", depth)
+	var buffer bytes.Buffer
+	buffer.WriteString("[\n")
+	for _, item := range items {
+		buffer.WriteString(fmt.Sprintf("%s  %s,\n", indent, formatValue(item, depth+1)))
+	}
+	buffer.WriteString(fmt.Sprintf("%s]", indent))
+	return buffer.String()
+}
+
+var identifierRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]*$`)
+
+func formatMap(items map[string]interface{}, depth int) string {
+	if len(items) == 0 {
+		return "{}"
+	}
+	var keys []string
+	width := 0
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var formattedKeys = map[string]string{}
+	for _, key := range keys {
+		formattedKey := key
+		if !identifierRegex.MatchString(key) {
+			formattedKey = formatString(key)
+		}
+		formattedKeys[key] = formattedKey
+		if len(formattedKey) > width {
+			width = len(formattedKey)
+		}
+	}
+	indent := strings.Repeat("  ", depth)
+	var buffer bytes.Buffer
+	buffer.WriteString("{\n")
+	for _, key := range keys {
+		buffer.WriteString(fmt.Sprintf("%s  %-*s = %s\n", indent, width, formattedKeys[key], formatValue(items[key], depth+1)))
+	}
+	buffer.WriteString(fmt.Sprintf("%s}", indent))
+	return buffer.String()
+}
@@ -0,0 +1,100 @@
+package tfgen
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestResourceName(t *testing.T) {
+	type test struct {
+		title    string
+		expected string
+	}
+	tests := []test{
+		{"Production", "production"},
+		{"My Folder (EU)", "my_folder_eu"},
+		{"aws-prod", "aws_prod"},
+		{"123 accounts", "_123_accounts"},
+		{"", "_"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, resourceName(test.title), test.title)
+	}
+}
+
+func TestFormatString(t *testing.T) {
+	type test struct {
+		name     string
+		value    string
+		expected string
+	}
+	tests := []test{
+		{"Simple", "Enforce: Enabled", `"Enforce: Enabled"`},
+		{"Quotes", `say "hi"`, `"say \"hi\""`},
+		{"Interpolation", "${var.x} %{if}", `"$${var.x} %%{if}"`},
+		{"Trailing newline", "value\n", `"value\n"`},
+		{"Multiline", "- a\n- b\n", "<<EOT\n- a\n- b\nEOT"},
+		{"Multiline without trailing newline", "- a\n- b", "<<EOT\n- a\n- b\nEOT"},
+		{"Multiline containing delimiter", "a\nEOT\nb", `"a\nEOT\nb"`},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, formatString(test.value), test.name)
+	}
+}
+
+func TestWriteConfig(t *testing.T) {
+	blocks := []*Block{
+		{
+			Type:     "turbot_folder",
+			Name:     "production",
+			ImportId: "111",
+			Attributes: []Attribute{
+				{"parent", "tmod:@turbot/turbot#/"},
+				{"title", "Production"},
+				{"tags", map[string]string{"env": "prod", "cost:centre": "a"}},
+			},
+		},
+		{
+			Type:     "turbot_smart_folder",
+			Name:     "eu",
+			ImportId: "222",
+			Comments: []string{"an example comment"},
+			Attributes: []Attribute{
+				{"parent", Reference("turbot_folder.production.id")},
+				{"filters", []interface{}{"a", "b"}},
+				{"policy", []NestedBlock{{Attributes: []Attribute{{"type", "tmod:@turbot/aws#/policy/types/regionsDefault"}, {"value", "- eu-west-1\n"}}}}},
+				{"policy", []NestedBlock{{Attributes: []Attribute{{"type", "tmod:@turbot/turbot#/policy/types/secret"}, {"value", Commented(`"<secret>"`)}}}}},
+			},
+		},
+	}
+	expected := `resource "turbot_folder" "production" {
+  parent = "tmod:@turbot/turbot#/"
+  title  = "Production"
+  tags   = {
+    "cost:centre" = "a"
+    env           = "prod"
+  }
+}
+
+# an example comment
+resource "turbot_smart_folder" "eu" {
+  parent  = turbot_folder.production.id
+  filters = [
+    "a",
+    "b",
+  ]
+
+  policy {
+    type  = "tmod:@turbot/aws#/policy/types/regionsDefault"
+    value = "- eu-west-1\n"
+  }
+
+  policy {
+    type = "tmod:@turbot/turbot#/policy/types/secret"
+    # value = "<secret>"
+  }
+}
+`
+	assert.Equal(t, expected, WriteConfig(blocks))
+	assert.Equal(t, "terraform import turbot_folder.production 111\nterraform import turbot_smart_folder.eu 222\n", WriteImportCommands(blocks))
+}
@@ -0,0 +1,94 @@
+package turbot
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/terraform-providers/terraform-provider-turbot/apiClient"
+)
+
+func dataSourceTurbotCallerIdentity() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceTurbotCallerIdentityRead,
+		Schema: map[string]*schema.Schema{
+			"profile_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"title": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"email": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"akas": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			// id of the directory the identity belongs to
+			"directory": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"grants": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"resource": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"level": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceTurbotCallerIdentityRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	identity, err := client.ReadCallerIdentity()
+	if err != nil {
+		return err
+	}
+
+	var grants []map[string]interface{}
+	for _, grant := range identity.Grants {
+		grants = append(grants, map[string]interface{}{
+			"id":       grant.Turbot.Id,
+			"resource": grant.Turbot.ResourceId,
+			"type":     grant.PermissionTypeId,
+			"level":    grant.PermissionLevelId,
+		})
+	}
+
+	d.SetId(identity.Id)
+	d.Set("profile_id", identity.ProfileId)
+	d.Set("title", identity.Title)
+	d.Set("email", identity.Email)
+	d.Set("status", identity.Status)
+	d.Set("akas", identity.Akas)
+	d.Set("directory", identity.Directory)
+	d.Set("grants", grants)
+	return nil
+}
@@ -0,0 +1,29 @@
+package turbot
+
+import (
+	"github.com/hashicorp/terraform/helper/resource"
+	"testing"
+)
+
+func TestAccCallerIdentityDataSource_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCallerIdentityDataSourceConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.turbot_caller_identity.test", "id"),
+					resource.TestCheckResourceAttrSet("data.turbot_caller_identity.test", "directory"),
+					resource.TestCheckResourceAttr("data.turbot_caller_identity.test", "status", "Active"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCallerIdentityDataSourceConfig() string {
+	return `
+data "turbot_caller_identity" "test" {}
+`
+}
@@ -0,0 +1,42 @@
+package turbot
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/terraform-providers/terraform-provider-turbot/apiClient"
+)
+
+// report which source supplied each of the credentials used by the provider
+func dataSourceTurbotCredentials() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceTurbotCredentialsRead,
+		Schema: map[string]*schema.Schema{
+			"workspace": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"access_key_source": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"secret_key_source": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"workspace_source": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceTurbotCredentialsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+
+	d.SetId(client.Workspace)
+	d.Set("workspace", client.Workspace)
+	d.Set("access_key_source", client.CredentialsSources.AccessKey)
+	d.Set("secret_key_source", client.CredentialsSources.SecretKey)
+	d.Set("workspace_source", client.CredentialsSources.Workspace)
+	return nil
+}
@@ -0,0 +1,30 @@
+package turbot
+
+import (
+	"github.com/hashicorp/terraform/helper/resource"
+	"testing"
+)
+
+func TestAccCredentialsDataSource_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCredentialsDataSourceConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.turbot_credentials.test", "workspace"),
+					resource.TestCheckResourceAttrSet("data.turbot_credentials.test", "access_key_source"),
+					resource.TestCheckResourceAttrSet("data.turbot_credentials.test", "secret_key_source"),
+					resource.TestCheckResourceAttrSet("data.turbot_credentials.test", "workspace_source"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCredentialsDataSourceConfig() string {
+	return `
+data "turbot_credentials" "test" {}
+`
+}
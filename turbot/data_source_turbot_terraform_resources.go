@@ -0,0 +1,117 @@
+package turbot
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/terraform-providers/terraform-provider-turbot/apiClient"
+)
+
+func dataSourceTurbotTerraformResources() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceTurbotTerraformResourcesRead,
+		Schema: map[string]*schema.Schema{
+			// aka of the root of the subtree to search
+			"resource": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			// additional Turbot filter, e.g. to restrict the resource types
+			"filter": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			// if set, only return resources with this owner
+			"owner": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			// if set, only return resources managed from this Terraform workspace
+			"terraform_workspace": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"resources": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"title": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"akas": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"owner": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"terraform_workspace": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"module_address": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						// the Terraform resource type which manages the resource, e.g. turbot_folder
+						"resource_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceTurbotTerraformResourcesRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	resourceAka := d.Get("resource").(string)
+	managedResources, err := client.ReadTerraformManagedResources(resourceAka, d.Get("filter").(string))
+	if err != nil {
+		return err
+	}
+
+	owner, ownerSet := d.GetOk("owner")
+	workspace, workspaceSet := d.GetOk("terraform_workspace")
+	var resources []map[string]interface{}
+	for _, resource := range managedResources {
+		terraform := resource.Terraform
+		if ownerSet && terraform["owner"] != owner {
+			continue
+		}
+		if workspaceSet && terraform["workspace"] != workspace {
+			continue
+		}
+		resources = append(resources, map[string]interface{}{
+			"id":                  resource.Id,
+			"title":               resource.Title,
+			"akas":                resource.Akas,
+			"owner":               terraformMetadataString(terraform, "owner"),
+			"terraform_workspace": terraformMetadataString(terraform, "workspace"),
+			"module_address":      terraformMetadataString(terraform, "module"),
+			"resource_type":       terraformMetadataString(terraform, "resourceType"),
+		})
+	}
+
+	d.SetId(resourceAka)
+	d.Set("resources", resources)
+	return nil
+}
+
+func terraformMetadataString(terraform map[string]interface{}, key string) string {
+	if value, ok := terraform[key]; ok && value != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return ""
+}
@@ -0,0 +1,43 @@
+package turbot
+
+import (
+	"github.com/hashicorp/terraform/helper/resource"
+	"testing"
+)
+
+func TestAccTerraformResourcesDataSource_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckFolderDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTerraformResourcesDataSourceConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.turbot_terraform_resources.test", "resources.#", "2"),
+					resource.TestCheckResourceAttr("data.turbot_terraform_resources.test", "resources.0.resource_type", "turbot_folder"),
+					resource.TestCheckResourceAttr("data.turbot_terraform_resources.test", "resources.1.resource_type", "turbot_folder"),
+				),
+			},
+		},
+	})
+}
+
+func testAccTerraformResourcesDataSourceConfig() string {
+	return `
+resource "turbot_folder" "parent" {
+  parent = "tmod:@turbot/turbot#/"
+  title = "provider_test_terraform_resources"
+}
+
+resource "turbot_folder" "child" {
+  parent = turbot_folder.parent.id
+  title = "provider_test_terraform_resources_child"
+}
+
+data "turbot_terraform_resources" "test" {
+  resource = turbot_folder.parent.id
+  depends_on = [turbot_folder.child]
+}
+`
+}
@@ -0,0 +1,114 @@
+package turbot
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/terraform-providers/terraform-provider-turbot/apiClient"
+	"regexp"
+	"strings"
+)
+
+var turbotIdRegex = regexp.MustCompile(`^\d+$`)
+
+// the import id may be a Turbot id or an aka (e.g. an ARN, 'tmod:@turbot/aws' or a resource path)
+// if it is an aka, resolve it to the Turbot id of the resource and update the id
+func resolveImportId(d *schema.ResourceData, meta interface{}) error {
+	id := d.Id()
+	if turbotIdRegex.MatchString(id) {
+		return nil
+	}
+	client := meta.(*apiClient.Client)
+	resource, err := client.ReadResource(id, nil)
+	if err != nil {
+		return fmt.Errorf("failed to resolve import id %s: %s", id, err.Error())
+	}
+	d.SetId(resource.Turbot.Id)
+	return nil
+}
+
+// split a composite import id of the form 'a:b:...' into 'count' parts
+// each part may be a Turbot id or an aka, which may itself contain colons:
+// - a 'tmod:' aka contains a single colon, e.g. tmod:@turbot/aws#/policy/types/regionsDefault
+// - an ARN contains five colons, e.g. arn:aws:s3:::my-bucket
+// the final part is always the remainder of the id
+func splitImportId(id string, count int) ([]string, error) {
+	tokens := strings.Split(id, ":")
+	var parts []string
+	for len(parts) < count-1 {
+		// the number of tokens making up the next part
+		length := 1
+		if len(tokens) > 0 {
+			switch tokens[0] {
+			case "tmod":
+				length = 2
+			case "arn":
+				length = 6
+			}
+		}
+		// there must be at least one token left for the final part
+		if len(tokens) <= length {
+			return nil, fmt.Errorf("invalid import id '%s' - expected %d ':' separated parts", id, count)
+		}
+		parts = append(parts, strings.Join(tokens[:length], ":"))
+		tokens = tokens[length:]
+	}
+	return append(parts, strings.Join(tokens, ":")), nil
+}
+
+// the policy setting import id may be a Turbot id or a composite key 'policyTypeUri:resourceAka'
+func resolvePolicySettingImportId(d *schema.ResourceData, meta interface{}) error {
+	id := d.Id()
+	if turbotIdRegex.MatchString(id) {
+		return nil
+	}
+	parts, err := splitImportId(id, 2)
+	if err != nil {
+		return err
+	}
+	policyTypeUri, resourceAka := parts[0], parts[1]
+
+	client := meta.(*apiClient.Client)
+	resourceIds, err := client.ResolveResourceIds(resourceAka)
+	if err != nil {
+		return err
+	}
+	settings, err := client.ReadResourcePolicySettings(resourceIds[0])
+	if err != nil {
+		return err
+	}
+	for _, setting := range settings {
+		if setting.Type.Uri == policyTypeUri {
+			d.SetId(setting.Turbot.Id)
+			return nil
+		}
+	}
+	return fmt.Errorf("no policy setting of type %s found on resource %s", policyTypeUri, resourceAka)
+}
+
+// the grant import id may be a Turbot id or a composite key 'identity:type:level:resource'
+func resolveGrantImportId(d *schema.ResourceData, meta interface{}) error {
+	id := d.Id()
+	if turbotIdRegex.MatchString(id) {
+		return nil
+	}
+	parts, err := splitImportId(id, 4)
+	if err != nil {
+		return err
+	}
+
+	client := meta.(*apiClient.Client)
+	// resolve the identity, permission type, permission level and resource akas
+	ids, err := client.ResolveResourceIds(parts...)
+	if err != nil {
+		return err
+	}
+	grant, err := client.FindGrant(ids[0], ids[1], ids[2], ids[3])
+	if err != nil {
+		return err
+	}
+	if grant == nil {
+		return fmt.Errorf("no grant of type %s level %s found for identity %s on resource %s", parts[1], parts[2], parts[0], parts[3])
+	}
+	d.SetId(grant.Turbot.Id)
+	return nil
+}
@@ -0,0 +1,72 @@
+package turbot
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/terraform-providers/terraform-provider-turbot/apiClient"
+	"testing"
+)
+
+func TestSplitImportId(t *testing.T) {
+	type test struct {
+		name     string
+		id       string
+		count    int
+		expected []string
+	}
+	tests := []test{
+		{
+			"Policy setting with resource id",
+			"tmod:@turbot/aws#/policy/types/regionsDefault:123456789012",
+			2,
+			[]string{"tmod:@turbot/aws#/policy/types/regionsDefault", "123456789012"},
+		},
+		{
+			"Policy setting with ARN",
+			"tmod:@turbot/aws-s3#/policy/types/bucketVersioning:arn:aws:s3:::my-bucket",
+			2,
+			[]string{"tmod:@turbot/aws-s3#/policy/types/bucketVersioning", "arn:aws:s3:::my-bucket"},
+		},
+		{
+			"Grant with akas",
+			"tmod:@turbot/turbot-iam#/profile/abc:tmod:@turbot/turbot-iam#/permission/types/turbot:tmod:@turbot/turbot-iam#/permission/levels/admin:tmod:@turbot/turbot#/",
+			4,
+			[]string{"tmod:@turbot/turbot-iam#/profile/abc", "tmod:@turbot/turbot-iam#/permission/types/turbot", "tmod:@turbot/turbot-iam#/permission/levels/admin", "tmod:@turbot/turbot#/"},
+		},
+		{
+			"Grant with ids and ARN",
+			"111:222:333:arn:aws:iam::123456789012:role/my-role",
+			4,
+			[]string{"111", "222", "333", "arn:aws:iam::123456789012:role/my-role"},
+		},
+		{
+			"ARN before final part",
+			"arn:aws:iam::123456789012:user/bob:tmod:@turbot/turbot#/",
+			2,
+			[]string{"arn:aws:iam::123456789012:user/bob", "tmod:@turbot/turbot#/"},
+		},
+	}
+	for _, test := range tests {
+		parts, err := splitImportId(test.id, test.count)
+		assert.Nil(t, err, test.name)
+		assert.Equal(t, test.expected, parts, test.name)
+	}
+}
+
+func TestSplitImportIdErrors(t *testing.T) {
+	_, err := splitImportId("tmod:@turbot/aws#/policy/types/regionsDefault", 2)
+	assert.NotNil(t, err)
+	_, err = splitImportId("111:222:333", 4)
+	assert.NotNil(t, err)
+}
+
+// the import id generated by turbot-tfgen must split into the identity, type, level and resource passed to FindGrant
+func TestGrantImportIdRoundTrip(t *testing.T) {
+	grant := apiClient.Grant{
+		Turbot:            apiClient.TurbotGrantMetadata{Id: "999", ProfileId: "111", ResourceId: "444"},
+		PermissionTypeId:  "222",
+		PermissionLevelId: "333",
+	}
+	parts, err := splitImportId(grant.ImportId(), 4)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"111", "222", "333", "444"}, parts)
+}
@@ -3,6 +3,7 @@ package turbot
 import (
 	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/terraform-providers/terraform-provider-turbot/apiClient"
 	"log"
@@ -31,27 +32,118 @@ func Provider() terraform.ResourceProvider {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			"credential_process": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			// if set, fail if the caller does not have Turbot/Admin on this resource
+			"validate_admin_on": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			// if set, all mutations are refused, so the provider can only read the workspace
+			"read_only": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			// if set, creating a policy setting, mod or folder which already exists adopts the existing object
+			"adopt_existing": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			// label identifying the configuration which owns the objects created by the provider - written to turbot.terraform
+			"owner": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			// Terraform does not pass the workspace or module address to providers, so they are configured explicitly
+			"terraform_workspace": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("TF_WORKSPACE", "default"),
+			},
+			"module_address": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			// what to do when adopting an object owned by another Terraform configuration - fail, or log a warning
+			"ownership_conflict": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "error",
+				ValidateFunc: validation.StringInSlice([]string{"error", "warn"}, false),
+			},
+			// tags applied to all resources which support tags - tags set on the resource take precedence
+			"default_tags": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"tags": {
+							Type:     schema.TypeMap,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+			// note applied to all policy settings which do not set a note
+			"default_policy_note": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			// tags which are managed outside Terraform - these are excluded when reading resource tags
+			"ignore_tags": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"keys": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"key_prefixes": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"turbot_policy_setting":          resourceTurbotPolicySetting(),
-			"turbot_mod":                     resourceTurbotMod(),
-			"turbot_folder":                  resourceTurbotFolder(),
-			"turbot_resource":                resourceTurbotResource(),
-			"turbot_local_directory":         resourceTurbotLocalDirectory(),
-			"turbot_profile":                 resourceTurbotProfile(),
-			"turbot_local_directory_user":    resourceTurbotLocalDirectoryUser(),
-			"turbot_google_directory":        resourceGoogleDirectory(),
-			"turbot_saml_directory":          resourceTurbotSamlDirectory(),
-			"turbot_shadow_resource":         resourceTurbotShadowResource(),
-			"turbot_smart_folder":            resourceTurbotSmartFolder(),
-			"turbot_smart_folder_attachment": resourceTurbotSmartFolderAttachemnt(),
-			"turbot_grant":                   resourceTurbotGrant(),
-			"turbot_grant_activation":        resourceTurbotGrantActivation(),
+			"turbot_policy_setting":           resourceTurbotPolicySetting(),
+			"turbot_mod":                      resourceTurbotMod(),
+			"turbot_folder":                   resourceTurbotFolder(),
+			"turbot_resource":                 resourceTurbotResource(),
+			"turbot_local_directory":          resourceTurbotLocalDirectory(),
+			"turbot_profile":                  resourceTurbotProfile(),
+			"turbot_local_directory_user":     resourceTurbotLocalDirectoryUser(),
+			"turbot_google_directory":         resourceGoogleDirectory(),
+			"turbot_saml_directory":           resourceTurbotSamlDirectory(),
+			"turbot_shadow_resource":          resourceTurbotShadowResource(),
+			"turbot_smart_folder":             resourceTurbotSmartFolder(),
+			"turbot_smart_folder_attachment":  resourceTurbotSmartFolderAttachemnt(),
+			"turbot_smart_folder_attachments": resourceTurbotSmartFolderAttachments(),
+			"turbot_grant":                    resourceTurbotGrant(),
+			"turbot_grant_activation":         resourceTurbotGrantActivation(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"turbot_policy_value": dataSourceTurbotPolicyValue(),
-			"turbot_resource":     dataSourceTurbotResource(),
+			"turbot_policy_value":        dataSourceTurbotPolicyValue(),
+			"turbot_resource":            dataSourceTurbotResource(),
+			"turbot_credentials":         dataSourceTurbotCredentials(),
+			"turbot_caller_identity":     dataSourceTurbotCallerIdentity(),
+			"turbot_terraform_resources": dataSourceTurbotTerraformResources(),
 		},
 
 		ConfigureFunc: providerConfigure,
@@ -65,17 +157,68 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 			SecretKey: d.Get("secret_key").(string),
 			Workspace: d.Get("workspace").(string),
 		},
-		Profile:         d.Get("profile").(string),
-		CredentialsPath: d.Get("credentials_file").(string),
+		Profile:           d.Get("profile").(string),
+		CredentialsPath:   d.Get("credentials_file").(string),
+		CredentialProcess: d.Get("credential_process").(string),
+		IgnoreTags:        buildIgnoreTagsConfig(d),
+		DefaultTags:       buildDefaultTags(d),
+		DefaultPolicyNote: d.Get("default_policy_note").(string),
+		ReadOnly:          d.Get("read_only").(bool),
+		AdoptExisting:     d.Get("adopt_existing").(bool),
+		Ownership: apiClient.OwnershipConfig{
+			Owner:          d.Get("owner").(string),
+			Workspace:      d.Get("terraform_workspace").(string),
+			Module:         d.Get("module_address").(string),
+			WarnOnConflict: d.Get("ownership_conflict").(string) == "warn",
+		},
 	}
 
 	client, err := apiClient.CreateClient(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create client: %s", err.Error())
 	}
-	log.Println("[INFO] Turbot API client initialized, now validating...", client)
+	// NOTE: do not log the client itself - it contains the access key and secret key
+	log.Println("[INFO] Turbot API client initialized, now validating...")
 	if err = client.Validate(); err != nil {
-		return nil, fmt.Errorf("failed to validate client: %s", err.Error())
+		// include the credential sources - the turbot_credentials data source cannot be read if validation fails
+		return nil, fmt.Errorf("failed to validate client (%s): %s", client.CredentialsSources, err.Error())
+	}
+	// detect the workspace version and features - if this fails, the most compatible queries are used
+	if err = client.DetectCapabilities(); err != nil {
+		log.Printf("[WARN] %s", err.Error())
+	}
+	if resourceAka, ok := d.GetOk("validate_admin_on"); ok {
+		if err = client.ValidateAdmin(resourceAka.(string)); err != nil {
+			return nil, fmt.Errorf("failed to validate client: %s", err.Error())
+		}
 	}
 	return client, nil
 }
+
+func buildIgnoreTagsConfig(d *schema.ResourceData) apiClient.IgnoreTagsConfig {
+	var config apiClient.IgnoreTagsConfig
+	ignoreTags := d.Get("ignore_tags").([]interface{})
+	if len(ignoreTags) == 0 || ignoreTags[0] == nil {
+		return config
+	}
+	ignoreTagsMap := ignoreTags[0].(map[string]interface{})
+	for _, key := range ignoreTagsMap["keys"].([]interface{}) {
+		config.Keys = append(config.Keys, key.(string))
+	}
+	for _, prefix := range ignoreTagsMap["key_prefixes"].([]interface{}) {
+		config.KeyPrefixes = append(config.KeyPrefixes, prefix.(string))
+	}
+	return config
+}
+
+func buildDefaultTags(d *schema.ResourceData) map[string]string {
+	var tags = map[string]string{}
+	defaultTags := d.Get("default_tags").([]interface{})
+	if len(defaultTags) == 0 || defaultTags[0] == nil {
+		return tags
+	}
+	for key, value := range defaultTags[0].(map[string]interface{})["tags"].(map[string]interface{}) {
+		tags[key] = value.(string)
+	}
+	return tags
+}
@@ -1,6 +1,8 @@
 package turbot
 
 import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/terraform-providers/terraform-provider-turbot/apiClient"
@@ -33,3 +35,15 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatal("No credentials are set - either set TURBOT_ACCESS_KEY, TURBOT_SECRET_KEY and TURBOT_WORKSPACE or populate the file ~/.config/turbot/credentials.yml")
 	}
 }
+
+// save the id of the resource so it can be used by a later step
+func testAccStoreId(resource string, id *string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[resource]
+		if !ok {
+			return fmt.Errorf("not found: %s", resource)
+		}
+		*id = rs.Primary.ID
+		return nil
+	}
+}
@@ -1,10 +1,12 @@
 package turbot
 
 import (
+	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/iancoleman/strcase"
 	"github.com/terraform-providers/terraform-provider-turbot/apiClient"
 	"github.com/terraform-providers/terraform-provider-turbot/helpers"
+	"log"
 )
 
 // given the resource data and a list of properties, construct a map of property values
@@ -23,7 +25,7 @@ func mapFromResourceData(d *schema.ResourceData, properties []interface{}) map[s
 		} else {
 			// otherwise perform automatic mapping from snake case (Terraform format) to lowerCamelCase (Turbot format).
 			terraformProperty := element.(string)
-			value, propertySet := d.GetOk(terraformProperty)
+			value, propertySet := getResourceDataValue(d, terraformProperty)
 			// if property is set, map it
 			if propertySet {
 				var turbotProperty = strcase.ToLowerCamel(terraformProperty)
@@ -39,7 +41,7 @@ func mapFromResourceDataWithPropertyMap(d *schema.ResourceData, terraformToTurbo
 	var resourcePropertyMap = map[string]interface{}{}
 	for terraform, turbot := range terraformToTurbotMap {
 		// get schema for property
-		value, propertySet := d.GetOk(terraform)
+		value, propertySet := getResourceDataValue(d, terraform)
 		if propertySet {
 			resourcePropertyMap[turbot] = value
 		}
@@ -47,6 +49,43 @@ func mapFromResourceDataWithPropertyMap(d *schema.ResourceData, terraformToTurbo
 	return resourcePropertyMap
 }
 
+// get the value of a property to pass to a create/update mutation, and whether it should be passed
+// GetOk does not distinguish a property which is unset from one which has been cleared, as both have the zero value.
+// When updating, a property which has been removed from the config must be passed explicitly to clear it in Turbot:
+// - removed strings are set to nil
+// - removed lists are set to an empty list, as lists are replaced
+// - removed map keys are set to nil (passing the new map alone would leave them in place)
+// - bools and numbers are passed as their new (zero) value
+// NOTE: ignored tags are never in state so they are not removed
+func getResourceDataValue(d *schema.ResourceData, property string) (interface{}, bool) {
+	value, propertySet := d.GetOk(property)
+	// when creating, there is nothing to clear
+	if d.Id() == "" || !d.HasChange(property) {
+		return value, propertySet
+	}
+	oldValue, newValue := d.GetChange(property)
+	if oldMap, ok := oldValue.(map[string]interface{}); ok {
+		var result = map[string]interface{}{}
+		for key := range oldMap {
+			result[key] = nil
+		}
+		for key, value := range newValue.(map[string]interface{}) {
+			result[key] = value
+		}
+		return result, true
+	}
+	if propertySet {
+		return value, true
+	}
+	switch newValue.(type) {
+	case string:
+		return nil, true
+	case []interface{}, *schema.Set:
+		return []interface{}{}, true
+	}
+	return newValue, true
+}
+
 // given a resource aka, fetch all akas for the resource and store in resourceData using 'propertyName'
 func storeAkas(aka, propertyName string, d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*apiClient.Client)
@@ -58,3 +97,177 @@ func storeAkas(aka, propertyName string, d *schema.ResourceData, meta interface{
 	d.Set(propertyName, akas)
 	return nil
 }
+
+// remove tags which are managed outside Terraform
+func filterIgnoredTags(tags map[string]string, ignoreTags apiClient.IgnoreTagsConfig) map[string]string {
+	var result = map[string]string{}
+	for key, value := range tags {
+		if !ignoreTags.IsIgnored(key) {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// pass the version id which was last read in the update input, as a precondition of the update
+// an empty version id (e.g. state written by an earlier provider version) is not passed
+func setVersionPrecondition(input map[string]interface{}, d *schema.ResourceData) {
+	if versionId := d.Get("version_id").(string); versionId != "" {
+		input["versionId"] = versionId
+	}
+}
+
+// explain the conflict error returned when the version precondition of an update fails
+func versionConflictError(objectType string, d *schema.ResourceData, err error) error {
+	return apiClient.VersionConflictError(objectType, d.Id(), d.Get("version_id").(string), err)
+}
+
+// merge the provider default tags into the tags input - tags set on the resource take precedence
+// a tag removed from the resource config (passed as nil) reverts to the default value
+func applyDefaultTags(input map[string]interface{}, meta interface{}) {
+	client := meta.(*apiClient.Client)
+	if len(client.DefaultTags) == 0 {
+		return
+	}
+	var tags = map[string]interface{}{}
+	if inputTags, ok := input["tags"].(map[string]interface{}); ok {
+		tags = inputTags
+	}
+	for key, value := range client.DefaultTags {
+		if tags[key] == nil {
+			tags[key] = value
+		}
+	}
+	input["tags"] = tags
+}
+
+// remove tags which were applied from the provider default tags rather than the resource config, so they do not cause a diff
+// a tag is only removed if it has the default value and was not previously set by the resource
+func removeDefaultTags(tags map[string]string, d *schema.ResourceData, meta interface{}) map[string]string {
+	client := meta.(*apiClient.Client)
+	stateTags := d.Get("tags").(map[string]interface{})
+	var result = map[string]string{}
+	for key, value := range tags {
+		defaultValue, isDefault := client.DefaultTags[key]
+		if _, inState := stateTags[key]; isDefault && value == defaultValue && !inState {
+			continue
+		}
+		result[key] = value
+	}
+	return result
+}
+
+// apply the provider default policy note if the policy setting does not set a note
+func applyDefaultPolicyNote(input map[string]interface{}, meta interface{}) {
+	client := meta.(*apiClient.Client)
+	if client.DefaultPolicyNote == "" {
+		return
+	}
+	if note, ok := input["note"]; !ok || note == nil {
+		input["note"] = client.DefaultPolicyNote
+	}
+}
+
+// if the note read from Turbot is the provider default and the policy setting does not set a note, store an empty note so it does not cause a diff
+func removeDefaultPolicyNote(note string, d *schema.ResourceData, meta interface{}) string {
+	client := meta.(*apiClient.Client)
+	if note != "" && note == client.DefaultPolicyNote && d.Get("note").(string) == "" {
+		return ""
+	}
+	return note
+}
+
+// the turbot.terraform metadata recording the Terraform configuration which owns an object
+func ownershipMetadata(resourceType string, meta interface{}) map[string]interface{} {
+	client := meta.(*apiClient.Client)
+	return client.Ownership.Metadata(resourceType)
+}
+
+// check an existing object is not managed by another Terraform configuration, given its turbot.terraform metadata
+// if ownership_conflict is 'warn', a warning is logged instead
+func checkOwnership(objectType, id string, ownership map[string]interface{}, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	if !client.Ownership.OwnedByOther(ownership) {
+		return nil
+	}
+	message := fmt.Sprintf("%s %s is managed by another Terraform configuration (owner: '%v', workspace: '%v', module: '%v')",
+		objectType, id, ownership["owner"], ownership["workspace"], ownership["module"])
+	if client.Ownership.WarnOnConflict {
+		log.Printf("[WARN] %s", message)
+		return nil
+	}
+	return fmt.Errorf("%s. To take ownership, set the provider argument ownership_conflict = \"warn\"", message)
+}
+
+// take ownership of an existing resource, stamping it with this Terraform configuration
+func claimOwnership(resource *apiClient.TurbotResourceMetadata, resourceType string, meta interface{}) error {
+	if err := checkOwnership("resource", resource.Id, resource.Terraform, meta); err != nil {
+		return err
+	}
+	return stampOwnership(resource.Id, resourceType, meta)
+}
+
+// stamp a resource with the turbot.terraform metadata of this Terraform configuration
+// used for resources whose create mutation does not accept the metadata, e.g. installed mods
+func stampOwnership(id, resourceType string, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	_, err := client.UpdateResource(map[string]interface{}{
+		"id":        id,
+		"terraform": ownershipMetadata(resourceType, meta),
+	})
+	return err
+}
+
+// refuse to destroy a resource which has deletion_protection enabled
+func checkDeletionProtection(d *schema.ResourceData, resourceType string) error {
+	if d.Get("deletion_protection").(bool) {
+		return fmt.Errorf("cannot destroy %s %s: deletion_protection is enabled. Set deletion_protection = false and apply before destroying", resourceType, d.Id())
+	}
+	return nil
+}
+
+// refuse a plan which replaces a resource which has deletion_protection enabled
+// forceNewKeys are the ForceNew properties of the resource which are compared directly (i.e. which have no DiffSuppressFunc)
+func customizeDiffDeletionProtection(d *schema.ResourceDiff, resourceType string, forceNewKeys []string) error {
+	if d.Id() == "" {
+		return nil
+	}
+	// use the current protection - disabling protection and replacing the resource in a single apply is not allowed
+	protected, _ := d.GetChange("deletion_protection")
+	if !protected.(bool) {
+		return nil
+	}
+	for _, key := range forceNewKeys {
+		if d.HasChange(key) {
+			return fmt.Errorf("cannot replace %s %s: changing '%s' requires replacement but deletion_protection is enabled. Set deletion_protection = false and apply before making this change", resourceType, d.Id(), key)
+		}
+	}
+	return nil
+}
+
+// adopt_existing may be set on the resource or provider-wide
+func adoptExistingEnabled(adoptExisting bool, meta interface{}) bool {
+	return adoptExisting || meta.(*apiClient.Client).AdoptExisting
+}
+
+// when creating a resource with adopt_existing enabled, find the existing object and show its id in the plan
+// findExisting returns the id of the existing object, or an empty string if there is none
+func customizeDiffAdoptedId(d *schema.ResourceDiff, meta interface{}, keys []string, findExisting func() (string, error)) error {
+	if d.Id() != "" {
+		return nil
+	}
+	if !adoptExistingEnabled(d.Get("adopt_existing").(bool), meta) {
+		return d.SetNew("adopted_id", "")
+	}
+	// if the properties identifying the object are not known yet, the adopted id will be known after apply
+	for _, key := range keys {
+		if !d.NewValueKnown(key) {
+			return nil
+		}
+	}
+	existingId, err := findExisting()
+	if err != nil {
+		return err
+	}
+	return d.SetNew("adopted_id", existingId)
+}
@@ -0,0 +1,268 @@
+package turbot
+
+import (
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/terraform-providers/terraform-provider-turbot/apiClient"
+	"testing"
+)
+
+// build the ResourceData passed to an update, given the current state attributes and the new config
+func testUpdateResourceData(t *testing.T, resource *schema.Resource, state map[string]string, raw map[string]interface{}) *schema.ResourceData {
+	t.Helper()
+	c, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	schemaMap := schema.InternalMap(resource.Schema)
+	instanceState := &terraform.InstanceState{ID: "123456789012", Attributes: state}
+	diff, err := schemaMap.Diff(instanceState, terraform.NewResourceConfig(c), nil, nil, true)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	d, err := schemaMap.Data(instanceState, diff)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return d
+}
+
+func TestMapFromResourceDataClearsRemovedProperties(t *testing.T) {
+	type test struct {
+		name       string
+		resource   *schema.Resource
+		properties []interface{}
+		state      map[string]string
+		config     map[string]interface{}
+		expected   map[string]interface{}
+	}
+	tests := []test{
+		{
+			"Folder description removed",
+			resourceTurbotFolder(),
+			folderDataProperties,
+			map[string]string{"parent": "tmod:@turbot/turbot#/", "title": "folder", "description": "test folder"},
+			map[string]interface{}{"parent": "tmod:@turbot/turbot#/", "title": "folder"},
+			map[string]interface{}{"title": "folder", "description": nil},
+		},
+		{
+			"Folder tag removed",
+			resourceTurbotFolder(),
+			folderInputProperties,
+			map[string]string{"parent": "tmod:@turbot/turbot#/", "title": "folder", "tags.%": "2", "tags.Name": "folder", "tags.Environment": "test"},
+			map[string]interface{}{"parent": "tmod:@turbot/turbot#/", "title": "folder", "tags": map[string]interface{}{"Name": "folder"}},
+			map[string]interface{}{"parent": "tmod:@turbot/turbot#/", "tags": map[string]interface{}{"Name": "folder", "Environment": nil}},
+		},
+		{
+			"Folder all tags removed",
+			resourceTurbotFolder(),
+			folderInputProperties,
+			map[string]string{"parent": "tmod:@turbot/turbot#/", "title": "folder", "tags.%": "1", "tags.Name": "folder"},
+			map[string]interface{}{"parent": "tmod:@turbot/turbot#/", "title": "folder"},
+			map[string]interface{}{"parent": "tmod:@turbot/turbot#/", "tags": map[string]interface{}{"Name": nil}},
+		},
+		{
+			"Policy setting note, template and valid_to_timestamp removed",
+			resourceTurbotPolicySetting(),
+			getPolicySettingUpdateProperties(),
+			map[string]string{
+				"type":                 "tmod:@turbot/aws#/policy/types/regionsDefault",
+				"resource":             "tmod:@turbot/turbot#/",
+				"value":                "us-east-1",
+				"precedence":           "REQUIRED",
+				"note":                 "a note",
+				"template":             "{{ 'us-east-1' }}",
+				"valid_to_timestamp":   "2030-01-01T00:00:00Z",
+				"valid_from_timestamp": "2020-01-01T00:00:00Z",
+			},
+			map[string]interface{}{
+				"type":                 "tmod:@turbot/aws#/policy/types/regionsDefault",
+				"resource":             "tmod:@turbot/turbot#/",
+				"value":                "us-east-1",
+				"valid_from_timestamp": "2020-01-01T00:00:00Z",
+			},
+			map[string]interface{}{
+				"value":              "us-east-1",
+				"precedence":         "REQUIRED",
+				"validFromTimestamp": "2020-01-01T00:00:00Z",
+				"note":               nil,
+				"template":           nil,
+				"validToTimestamp":   nil,
+			},
+		},
+		{
+			"Local directory description removed",
+			resourceTurbotLocalDirectory(),
+			localDirectoryDataProperties,
+			map[string]string{"parent": "tmod:@turbot/turbot#/", "title": "directory", "profile_id_template": "{{profile.email}}", "description": "test directory"},
+			map[string]interface{}{"parent": "tmod:@turbot/turbot#/", "title": "directory", "profile_id_template": "{{profile.email}}"},
+			map[string]interface{}{"title": "directory", "profileIdTemplate": "{{profile.email}}", "description": nil},
+		},
+		{
+			"Local directory user names removed",
+			resourceTurbotLocalDirectoryUser(),
+			localDirectoryUserDataProperties,
+			map[string]string{"parent": "123", "title": "user", "email": "user@example.com", "display_name": "User", "given_name": "Given", "middle_name": "Middle"},
+			map[string]interface{}{"parent": "123", "title": "user", "email": "user@example.com", "display_name": "User"},
+			map[string]interface{}{"title": "user", "email": "user@example.com", "displayName": "User", "givenName": nil, "middleName": nil},
+		},
+		{
+			"Unchanged unset properties are not passed",
+			resourceTurbotFolder(),
+			folderDataProperties,
+			map[string]string{"parent": "tmod:@turbot/turbot#/", "title": "folder"},
+			map[string]interface{}{"parent": "tmod:@turbot/turbot#/", "title": "new title"},
+			map[string]interface{}{"title": "new title"},
+		},
+	}
+
+	for _, test := range tests {
+		d := testUpdateResourceData(t, test.resource, test.state, test.config)
+		assert.Equal(t, test.expected, mapFromResourceData(d, test.properties), test.name)
+	}
+}
+
+func TestMapFromResourceDataCreateOmitsUnsetProperties(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceTurbotFolder().Schema, map[string]interface{}{
+		"parent": "tmod:@turbot/turbot#/",
+		"title":  "folder",
+	})
+	assert.Equal(t, map[string]interface{}{"title": "folder"}, mapFromResourceData(d, folderDataProperties))
+}
+
+func TestCheckOwnership(t *testing.T) {
+	ownership := apiClient.OwnershipConfig{Owner: "network", Workspace: "prod"}
+	owned := ownership.Metadata("turbot_folder")
+	other := apiClient.OwnershipConfig{Owner: "security", Workspace: "prod"}.Metadata("turbot_folder")
+
+	client := &apiClient.Client{Ownership: ownership}
+	assert.Nil(t, checkOwnership("folder", "123", owned, client))
+	assert.Nil(t, checkOwnership("folder", "789", nil, client))
+	if err := checkOwnership("folder", "456", other, client); assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "folder 456 is managed by another Terraform configuration (owner: 'security'")
+	}
+
+	// if ownership_conflict is 'warn', the conflict is only logged
+	ownership.WarnOnConflict = true
+	client = &apiClient.Client{Ownership: ownership}
+	assert.Nil(t, checkOwnership("folder", "456", other, client))
+}
+
+func TestApplyDefaultTags(t *testing.T) {
+	client := &apiClient.Client{DefaultTags: map[string]string{"Owner": "network", "Environment": "dev"}}
+	type test struct {
+		name     string
+		input    map[string]interface{}
+		expected interface{}
+	}
+	tests := []test{
+		{
+			"No tags set",
+			map[string]interface{}{},
+			map[string]interface{}{"Owner": "network", "Environment": "dev"},
+		},
+		{
+			"Resource tag overrides default",
+			map[string]interface{}{"tags": map[string]interface{}{"Environment": "prod", "Name": "test"}},
+			map[string]interface{}{"Owner": "network", "Environment": "prod", "Name": "test"},
+		},
+		{
+			"Removed tag reverts to default",
+			map[string]interface{}{"tags": map[string]interface{}{"Environment": nil, "Name": nil}},
+			map[string]interface{}{"Owner": "network", "Environment": "dev", "Name": nil},
+		},
+	}
+	for _, test := range tests {
+		applyDefaultTags(test.input, client)
+		assert.Equal(t, test.expected, test.input["tags"], test.name)
+	}
+
+	// without default tags the input is unchanged
+	input := map[string]interface{}{}
+	applyDefaultTags(input, &apiClient.Client{})
+	assert.Equal(t, map[string]interface{}{}, input)
+}
+
+func TestRemoveDefaultTags(t *testing.T) {
+	client := &apiClient.Client{DefaultTags: map[string]string{"Owner": "network", "Environment": "dev"}}
+	d := schema.TestResourceDataRaw(t, resourceTurbotResource().Schema, map[string]interface{}{
+		"tags": map[string]interface{}{"Environment": "dev", "Name": "test"},
+	})
+	tags := map[string]string{"Owner": "network", "Environment": "dev", "Name": "test", "CostCenter": "123"}
+	// Owner has the default value and is not set by the resource, so it is removed
+	expected := map[string]string{"Environment": "dev", "Name": "test", "CostCenter": "123"}
+	assert.Equal(t, expected, removeDefaultTags(tags, d, client))
+
+	// a default tag changed outside Terraform is kept, so it shows as drift
+	tags["Owner"] = "security"
+	expected["Owner"] = "security"
+	assert.Equal(t, expected, removeDefaultTags(tags, d, client))
+}
+
+func TestDefaultPolicyNote(t *testing.T) {
+	client := &apiClient.Client{DefaultPolicyNote: "Managed by Terraform"}
+
+	input := map[string]interface{}{}
+	applyDefaultPolicyNote(input, client)
+	assert.Equal(t, "Managed by Terraform", input["note"])
+
+	// a removed note reverts to the default
+	input = map[string]interface{}{"note": nil}
+	applyDefaultPolicyNote(input, client)
+	assert.Equal(t, "Managed by Terraform", input["note"])
+
+	input = map[string]interface{}{"note": "Approved by security"}
+	applyDefaultPolicyNote(input, client)
+	assert.Equal(t, "Approved by security", input["note"])
+
+	d := schema.TestResourceDataRaw(t, resourceTurbotPolicySetting().Schema, map[string]interface{}{})
+	assert.Equal(t, "", removeDefaultPolicyNote("Managed by Terraform", d, client))
+	assert.Equal(t, "Changed in console", removeDefaultPolicyNote("Changed in console", d, client))
+	d = schema.TestResourceDataRaw(t, resourceTurbotPolicySetting().Schema, map[string]interface{}{"note": "Managed by Terraform"})
+	assert.Equal(t, "Managed by Terraform", removeDefaultPolicyNote("Managed by Terraform", d, client))
+}
+
+func TestCheckDeletionProtection(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceTurbotFolder().Schema, map[string]interface{}{
+		"parent":              "tmod:@turbot/turbot#/",
+		"title":               "folder",
+		"deletion_protection": true,
+	})
+	d.SetId("123")
+	if err := checkDeletionProtection(d, "turbot_folder"); assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "cannot destroy turbot_folder 123: deletion_protection is enabled")
+	}
+	d.Set("deletion_protection", false)
+	assert.Nil(t, checkDeletionProtection(d, "turbot_folder"))
+}
+
+func TestCustomizeDiffDeletionProtection(t *testing.T) {
+	customizeDiff := func(d *schema.ResourceDiff, meta interface{}) error {
+		return customizeDiffDeletionProtection(d, "turbot_mod", []string{"org", "mod"})
+	}
+	diff := func(state map[string]string, raw map[string]interface{}) error {
+		c, err := config.NewRawConfig(raw)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		instanceState := &terraform.InstanceState{ID: "123", Attributes: state}
+		_, err = schema.InternalMap(resourceTurbotMod().Schema).Diff(instanceState, terraform.NewResourceConfig(c), customizeDiff, nil, true)
+		return err
+	}
+	state := map[string]string{"parent": "tmod:@turbot/turbot#/", "org": "turbot", "mod": "aws", "version": "*", "deletion_protection": "true"}
+
+	// replacing a protected mod is refused
+	err := diff(state, map[string]interface{}{"org": "turbot", "mod": "aws-s3", "deletion_protection": true})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "cannot replace turbot_mod 123: changing 'mod' requires replacement but deletion_protection is enabled")
+	}
+	// disabling protection in the same plan is also refused
+	assert.NotNil(t, diff(state, map[string]interface{}{"org": "turbot", "mod": "aws-s3"}))
+	// an in-place change is allowed
+	assert.Nil(t, diff(state, map[string]interface{}{"org": "turbot", "mod": "aws", "version": "^5", "deletion_protection": true}))
+
+	state["deletion_protection"] = "false"
+	assert.Nil(t, diff(state, map[string]interface{}{"org": "turbot", "mod": "aws-s3"}))
+}
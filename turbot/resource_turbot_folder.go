@@ -1,8 +1,11 @@
 package turbot
 
 import (
+	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/terraform-providers/terraform-provider-turbot/apiClient"
+	"log"
+	"strings"
 )
 
 // properties which must be passed to a create/update call
@@ -36,6 +39,23 @@ func resourceTurbotFolder() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			// the version of the folder when it was last read - updates fail if it has been modified since
+			"version_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			// if set, destroying or replacing the folder fails
+			"deletion_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			// if set, destroying the folder first deletes everything below it, along with their policy settings and grants
+			"force_destroy": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			"title": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -51,10 +71,36 @@ func resourceTurbotFolder() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			// if set, create adopts an existing folder with the same parent and title rather than failing
+			"adopt_existing": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			// the id of the existing folder adopted by create
+			"adopted_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 		},
+		CustomizeDiff: resourceTurbotFolderCustomizeDiff,
 	}
 }
 
+func resourceTurbotFolderCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	return customizeDiffAdoptedId(d, meta, []string{"parent", "title"}, func() (string, error) {
+		client := meta.(*apiClient.Client)
+		folder, err := client.FindFolder(d.Get("parent").(string), d.Get("title").(string))
+		if err != nil || folder == nil {
+			return "", err
+		}
+		// fail the plan if the folder is managed by another Terraform configuration
+		if err := checkOwnership("folder", folder.Id, folder.Terraform, meta); err != nil {
+			return "", err
+		}
+		return folder.Id, nil
+	})
+}
+
 func resourceTurbotFolderExists(d *schema.ResourceData, meta interface{}) (b bool, e error) {
 	client := meta.(*apiClient.Client)
 	id := d.Id()
@@ -64,10 +110,34 @@ func resourceTurbotFolderExists(d *schema.ResourceData, meta interface{}) (b boo
 func resourceTurbotFolderCreate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*apiClient.Client)
 
+	if adoptExistingEnabled(d.Get("adopt_existing").(bool), meta) {
+		existingFolder, err := client.FindFolder(d.Get("parent").(string), d.Get("title").(string))
+		if err != nil {
+			return err
+		}
+		if existingFolder != nil {
+			// take ownership of the existing folder, updating it to match the config
+			log.Printf("[INFO] adopting existing folder %s", existingFolder.Id)
+			if err := claimOwnership(existingFolder, "turbot_folder", meta); err != nil {
+				return err
+			}
+			d.SetId(existingFolder.Id)
+			if err := resourceTurbotFolderUpdate(d, meta); err != nil {
+				return err
+			}
+			d.Set("adopted_id", existingFolder.Id)
+			return nil
+		}
+	}
+
 	// build mutation input
 	input := mapFromResourceData(d, folderInputProperties)
+	applyDefaultTags(input, meta)
 	input["data"] = mapFromResourceData(d, folderDataProperties)
 
+	// record the Terraform configuration which owns the folder
+	input["terraform"] = ownershipMetadata("turbot_folder", meta)
+
 	folder, err := client.CreateFolder(input)
 	if err != nil {
 		return err
@@ -80,6 +150,7 @@ func resourceTurbotFolderCreate(d *schema.ResourceData, meta interface{}) error
 
 	// assign the id
 	d.SetId(folder.Turbot.Id)
+	d.Set("adopted_id", "")
 	// set FolderProperties the way we get in Read query
 	d.Set("parent", folder.Parent)
 	d.Set("title", folder.Title)
@@ -89,20 +160,23 @@ func resourceTurbotFolderCreate(d *schema.ResourceData, meta interface{}) error
 
 func resourceTurbotFolderUpdate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*apiClient.Client)
-
 	// build mutation payload
 	input := mapFromResourceData(d, folderInputProperties)
+	applyDefaultTags(input, meta)
 	input["data"] = mapFromResourceData(d, folderDataProperties)
 	input["id"] = d.Id()
+	// Turbot rejects the update if the folder has been modified outside Terraform since it was last read
+	setVersionPrecondition(input, d)
 
 	folder, err := client.UpdateFolder(input)
 	if err != nil {
-		return err
+		return versionConflictError("folder", d, err)
 	}
 	// set FolderProperties the way we get in Read query
 	d.Set("parent", folder.Parent)
 	d.Set("title", folder.Title)
 	d.Set("description", folder.Description)
+	d.Set("version_id", folder.Turbot.VersionId)
 	// set parent_akas property by loading resource and fetching the akas
 	return storeAkas(folder.Turbot.ParentId, "parent_akas", d, meta)
 }
@@ -124,15 +198,25 @@ func resourceTurbotFolderRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("parent", folder.Parent)
 	d.Set("title", folder.Title)
 	d.Set("description", folder.Description)
+	d.Set("version_id", folder.Turbot.VersionId)
 	// set parent_akas property by loading resource and fetching the akas
 	return storeAkas(folder.Turbot.ParentId, "parent_akas", d, meta)
 }
 
 func resourceTurbotFolderDelete(d *schema.ResourceData, meta interface{}) error {
+	if err := checkDeletionProtection(d, "turbot_folder"); err != nil {
+		return err
+	}
 	client := meta.(*apiClient.Client)
 	id := d.Id()
-	err := client.DeleteResource(id)
-	if err != nil {
+	if d.Get("force_destroy").(bool) {
+		deleted, err := client.DeleteResourceTree(id)
+		if err != nil {
+			return fmt.Errorf("force_destroy of folder %s failed: %s. Removed %s before the failure:\n  %s", id, err.Error(), deleted.Summary(), strings.Join(deleted.Descriptions(), "\n  "))
+		}
+		// record the removed objects at WARN level, so they are shown with any TF_LOG level except ERROR
+		log.Printf("[WARN] force_destroy of folder %s removed %s:\n  %s", id, deleted.Summary(), strings.Join(deleted.Descriptions(), "\n  "))
+	} else if err := client.DeleteResource(id); err != nil {
 		return err
 	}
 
@@ -143,6 +227,12 @@ func resourceTurbotFolderDelete(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceTurbotFolderImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := resolveImportId(d, meta); err != nil {
+		return nil, err
+	}
+	// set the defaults, so an imported resource does not show a diff
+	d.Set("deletion_protection", false)
+	d.Set("force_destroy", false)
 	if err := resourceTurbotFolderRead(d, meta); err != nil {
 		return nil, err
 	}
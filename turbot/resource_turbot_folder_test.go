@@ -3,8 +3,10 @@ package turbot
 import (
 	"fmt"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/terraform-providers/terraform-provider-turbot/apiClient"
+	"regexp"
 	"testing"
 )
 
@@ -44,6 +46,15 @@ func TestAccFolder_Basic(t *testing.T) {
 						"turbot_folder.test", "tags.Environment", "foo"),
 				),
 			},
+			{
+				// removing the description and tags clears them
+				Config: testAccFolderClearedConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"turbot_folder.test", "description", ""),
+					testAccCheckFolderCleared("turbot_folder.test"),
+				),
+			},
 		},
 	})
 }
@@ -103,6 +114,111 @@ func TestAccFolder_Dependencies(t *testing.T) {
 	})
 }
 
+func TestAccFolder_AdoptExisting(t *testing.T) {
+	var parentId, existingId string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckFolderDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFolderAdoptParentConfig(),
+				Check:  testAccStoreId("turbot_folder.parent", &parentId),
+			},
+			{
+				// create the folder outside Terraform, then adopt it
+				PreConfig: testAccCreateFolder(t, &parentId, "provider_test_adopt", &existingId),
+				Config:    testAccFolderAdoptConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckFolderExists("turbot_folder.adopted"),
+					testAccCheckFolderId("turbot_folder.adopted", &existingId),
+					resource.TestCheckResourceAttr(
+						"turbot_folder.adopted", "description", "adopted folder"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccFolder_VersionConflict(t *testing.T) {
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() { testAccPreCheck(t) },
+		Providers: map[string]terraform.ResourceProvider{
+			"turbot":    testAccProvider,
+			"outofband": testAccOutOfBandProvider(&id),
+		},
+		CheckDestroy: testAccCheckFolderDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFolderConfig(),
+				Check:  testAccStoreId("turbot_folder.test", &id),
+			},
+			{
+				// the folder is modified outside Terraform during the apply, after the refresh has read its version,
+				// so the update is rejected with a conflict
+				// NOTE: a change made in PreConfig is read by the refresh before the plan, so it is reported as drift instead
+				Config:      testAccFolderVersionConflictConfig(),
+				ExpectError: regexp.MustCompile("has been modified outside Terraform since it was last read"),
+			},
+		},
+	})
+}
+
+func TestAccFolder_DeletionProtection(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckFolderDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFolderDeletionProtectionConfig(true),
+				Check: resource.TestCheckResourceAttr(
+					"turbot_folder.test", "deletion_protection", "true"),
+			},
+			{
+				Config:      testAccFolderDeletionProtectionConfig(true),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("deletion_protection is enabled"),
+			},
+			{
+				// disable protection so the folder can be destroyed
+				Config: testAccFolderDeletionProtectionConfig(false),
+				Check: resource.TestCheckResourceAttr(
+					"turbot_folder.test", "deletion_protection", "false"),
+			},
+		},
+	})
+}
+
+func TestAccFolder_ForceDestroy(t *testing.T) {
+	var folderId, childId, grandchildId string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckFolderTreeDestroy(&childId, &grandchildId),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFolderForceDestroyConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccStoreId("turbot_folder.test", &folderId),
+					resource.TestCheckResourceAttr(
+						"turbot_folder.test", "force_destroy", "true"),
+				),
+			},
+			{
+				// create folders below the folder outside Terraform - destroy must remove them
+				PreConfig: func() {
+					testAccCreateFolder(t, &folderId, "provider_test_child", &childId)()
+					testAccCreateFolder(t, &childId, "provider_test_grandchild", &grandchildId)()
+				},
+				Config: testAccFolderForceDestroyConfig(),
+				Check:  testAccCheckFolderExists("turbot_folder.test"),
+			},
+		},
+	})
+}
+
 // configs
 func testAccFolderConfig() string {
 	return `
@@ -114,6 +230,21 @@ resource "turbot_folder" "test" {
 `
 }
 
+func testAccFolderVersionConflictConfig() string {
+	return `
+resource "outofband_update" "test" {
+	description = "modified outside Terraform"
+}
+
+resource "turbot_folder" "test" {
+	parent = "tmod:@turbot/turbot#/"
+	title = "provider_test"
+	description = "test folder for turbot terraform provider"
+	depends_on = [outofband_update.test]
+}
+`
+}
+
 func testAccFolderUpdateDescConfig() string {
 	return `
 resource "turbot_folder" "test" {
@@ -148,6 +279,35 @@ resource "turbot_folder" "test" {
 `
 }
 
+func testAccFolderClearedConfig() string {
+	return `
+resource "turbot_folder" "test" {
+	parent = "tmod:@turbot/turbot#/"
+	title = "provider_test_upd"
+}
+`
+}
+
+func testAccFolderDeletionProtectionConfig(deletionProtection bool) string {
+	return fmt.Sprintf(`
+resource "turbot_folder" "test" {
+	parent = "tmod:@turbot/turbot#/"
+	title = "provider_test_protected"
+	deletion_protection = %t
+}
+`, deletionProtection)
+}
+
+func testAccFolderForceDestroyConfig() string {
+	return `
+resource "turbot_folder" "test" {
+	parent = "tmod:@turbot/turbot#/"
+	title = "provider_test_force_destroy"
+	force_destroy = true
+}
+`
+}
+
 func testAccFolderWithDependenciesConfig() string {
 	return `
 resource "turbot_folder" "parent" {
@@ -192,7 +352,92 @@ resource "turbot_folder" "child" {
 `
 }
 
+func testAccFolderAdoptParentConfig() string {
+	return `
+resource "turbot_folder" "parent" {
+  parent = "tmod:@turbot/turbot#/"
+  title = "provider_test_adopt_parent"
+}
+`
+}
+
+func testAccFolderAdoptConfig() string {
+	return `
+resource "turbot_folder" "parent" {
+  parent = "tmod:@turbot/turbot#/"
+  title = "provider_test_adopt_parent"
+}
+resource "turbot_folder" "adopted" {
+  parent = "${turbot_folder.parent.id}"
+  title = "provider_test_adopt"
+  description = "adopted folder"
+  adopt_existing = true
+}
+`
+}
+
 // helper functions
+// create a folder outside Terraform, saving its id
+func testAccCreateFolder(t *testing.T, parentId *string, title string, id *string) func() {
+	return func() {
+		client := testAccProvider.Meta().(*apiClient.Client)
+		folder, err := client.CreateFolder(map[string]interface{}{
+			"parent": *parentId,
+			"data":   map[string]interface{}{"title": title},
+		})
+		if err != nil {
+			t.Fatalf("failed to create folder %s outside Terraform: %s", title, err)
+		}
+		*id = folder.Turbot.Id
+	}
+}
+
+// a provider whose resource updates the folder outside Terraform when it is created - as the folder depends on it,
+// the update is made during the apply, between the refresh and the update of the folder
+func testAccOutOfBandProvider(id *string) terraform.ResourceProvider {
+	return &schema.Provider{
+		ResourcesMap: map[string]*schema.Resource{
+			"outofband_update": {
+				Schema: map[string]*schema.Schema{
+					"description": {
+						Type:     schema.TypeString,
+						Required: true,
+						ForceNew: true,
+					},
+				},
+				Create: func(d *schema.ResourceData, meta interface{}) error {
+					client := testAccProvider.Meta().(*apiClient.Client)
+					input := map[string]interface{}{"id": *id, "data": map[string]interface{}{"description": d.Get("description").(string)}}
+					if _, err := client.UpdateResource(input); err != nil {
+						return fmt.Errorf("failed to update folder %s outside Terraform: %s", *id, err)
+					}
+					d.SetId(*id)
+					return nil
+				},
+				Read:   func(d *schema.ResourceData, meta interface{}) error { return nil },
+				Delete: func(d *schema.ResourceData, meta interface{}) error { return nil },
+			},
+		},
+	}
+}
+
+// check the resource id matches the id of an existing object
+func testAccCheckFolderId(resource string, id *string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[resource]
+		if !ok {
+			return fmt.Errorf("not found: %s", resource)
+		}
+		if rs.Primary.ID != *id {
+			return fmt.Errorf("expected id %s, got %s", *id, rs.Primary.ID)
+		}
+		if adoptedId := rs.Primary.Attributes["adopted_id"]; adoptedId != *id {
+			return fmt.Errorf("expected adopted_id %s, got %s", *id, adoptedId)
+		}
+		return nil
+	}
+}
+
 func testAccCheckFolderExists(resource string) resource.TestCheckFunc {
 	return func(state *terraform.State) error {
 		rs, ok := state.RootModule().Resources[resource]
@@ -211,6 +456,29 @@ func testAccCheckFolderExists(resource string) resource.TestCheckFunc {
 	}
 }
 
+// check the description and tags have been cleared in Turbot
+// NOTE: folder read does not read tags back, so they are checked through the API rather than the state
+func testAccCheckFolderCleared(resource string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[resource]
+		if !ok {
+			return fmt.Errorf("not found: %s", resource)
+		}
+		client := testAccProvider.Meta().(*apiClient.Client)
+		folder, err := client.ReadFolder(rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("error fetching item with resource %s. %s", resource, err)
+		}
+		if folder.Description != "" {
+			return fmt.Errorf("expected description to be cleared, got '%s'", folder.Description)
+		}
+		if len(folder.Turbot.Tags) > 0 {
+			return fmt.Errorf("expected tags to be cleared, got %v", folder.Turbot.Tags)
+		}
+		return nil
+	}
+}
+
 func testAccCheckFolderDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(*apiClient.Client)
 	for _, rs := range s.RootModule().Resources {
@@ -227,3 +495,19 @@ func testAccCheckFolderDestroy(s *terraform.State) error {
 
 	return nil
 }
+
+// check the folders created outside Terraform below a force destroyed folder have also been deleted
+func testAccCheckFolderTreeDestroy(ids ...*string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if err := testAccCheckFolderDestroy(s); err != nil {
+			return err
+		}
+		client := testAccProvider.Meta().(*apiClient.Client)
+		for _, id := range ids {
+			if _, err := client.ReadFolder(*id); err == nil || !apiClient.NotFoundError(err) {
+				return fmt.Errorf("expected folder %s to be deleted", *id)
+			}
+		}
+		return nil
+	}
+}
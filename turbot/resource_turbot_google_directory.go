@@ -44,6 +44,17 @@ func resourceGoogleDirectory() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			// the version of the directory when it was last read - updates fail if it has been modified since
+			"version_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			// if set, destroying or replacing the directory fails
+			"deletion_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			"title": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -106,9 +117,14 @@ func resourceGoogleDirectory() *schema.Resource {
 				},
 			},
 		},
+		CustomizeDiff: resourceTurbotGoogleDirectoryCustomizeDiff,
 	}
 }
 
+func resourceTurbotGoogleDirectoryCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	return customizeDiffDeletionProtection(d, "turbot_google_directory", []string{"pgp_key"})
+}
+
 func resourceTurbotGoogleDirectoryExists(d *schema.ResourceData, meta interface{}) (b bool, e error) {
 	client := meta.(*apiClient.Client)
 	id := d.Id()
@@ -119,12 +135,16 @@ func resourceTurbotGoogleDirectoryCreate(d *schema.ResourceData, meta interface{
 	client := meta.(*apiClient.Client)
 	// build mutation payload
 	input := mapFromResourceData(d, googleDirectoryInputProperties)
+	applyDefaultTags(input, meta)
 	data := mapFromResourceData(d, googleDirectoryDataProperties)
 	// set computed properties
 	data["status"] = "Active"
 	data["directoryType"] = "google"
 	input["data"] = data
 
+	// record the Terraform configuration which owns the google directory
+	input["terraform"] = ownershipMetadata("turbot_google_directory", meta)
+
 	turbotMetadata, err := client.CreateGoogleDirectory(input)
 	if err != nil {
 		return err
@@ -169,6 +189,7 @@ func resourceTurbotGoogleDirectoryRead(d *schema.ResourceData, meta interface{})
 	d.Set("group_id_template", googleDirectory.GroupIdTemplate)
 	d.Set("login_name_template", googleDirectory.LoginNameTemplate)
 	d.Set("hosted_name", googleDirectory.HostedName)
+	d.Set("version_id", googleDirectory.Turbot.VersionId)
 	// set parent_akas property by loading parent resource and fetching the akas
 	return storeAkas(googleDirectory.Turbot.ParentId, "parent_akas", d, meta)
 }
@@ -177,14 +198,18 @@ func resourceTurbotGoogleDirectoryUpdate(d *schema.ResourceData, meta interface{
 	client := meta.(*apiClient.Client)
 	// build mutation payload
 	input := mapFromResourceData(d, googleDirectoryInputProperties)
+	applyDefaultTags(input, meta)
 	data := mapFromResourceData(d, googleDirectoryDataProperties)
 	input["data"] = data
 	input["id"] = d.Id()
+	// Turbot rejects the update if the directory has been modified outside Terraform since it was last read
+	setVersionPrecondition(input, d)
 
 	turbotMetadata, err := client.UpdateGoogleDirectory(input)
 	if err != nil {
-		return err
+		return versionConflictError("directory", d, err)
 	}
+	d.Set("version_id", turbotMetadata.VersionId)
 	// set parent_akas property by loading parent resource and fetching the akas
 	if err := storeAkas(turbotMetadata.ParentId, "parent_akas", d, meta); err != nil {
 		return err
@@ -194,6 +219,9 @@ func resourceTurbotGoogleDirectoryUpdate(d *schema.ResourceData, meta interface{
 }
 
 func resourceTurbotGoogleDirectoryDelete(d *schema.ResourceData, meta interface{}) error {
+	if err := checkDeletionProtection(d, "turbot_google_directory"); err != nil {
+		return err
+	}
 	client := meta.(*apiClient.Client)
 	id := d.Id()
 	err := client.DeleteResource(id)
@@ -207,6 +235,11 @@ func resourceTurbotGoogleDirectoryDelete(d *schema.ResourceData, meta interface{
 }
 
 func resourceTurbotGoogleDirectoryImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := resolveImportId(d, meta); err != nil {
+		return nil, err
+	}
+	// set the default, so an imported resource does not show a diff
+	d.Set("deletion_protection", false)
 	if err := resourceTurbotGoogleDirectoryRead(d, meta); err != nil {
 		return nil, err
 	}
@@ -1,18 +1,32 @@
 package turbot
 
 import (
+	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/terraform-providers/terraform-provider-turbot/apiClient"
+	"log"
+	"time"
 )
 
 // map of Terraform properties to Turbot properties that we pass to create and update mutations
 // NOTE: use a map instead of an array like other resources as we cannot automatically map the names
 var grantInputProperties = []interface{}{"identity", "type", "level", "resource"}
 
+// the status of a grant with a validity period
+const (
+	grantStatusPending = "pending"
+	grantStatusActive  = "active"
+	grantStatusExpired = "expired"
+	// the grant has expired and been deleted from Turbot
+	grantStatusRemoved = "removed"
+)
+
 func resourceTurbotGrant() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceTurbotGrantCreate,
 		Read:   resourceTurbotGrantRead,
+		Update: resourceTurbotGrantUpdate,
 		Delete: resourceTurbotGrantDelete,
 		Exists: resourceTurbotGrantExists,
 		Importer: &schema.ResourceImporter{
@@ -80,14 +94,97 @@ func resourceTurbotGrant() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			// the start of the period the grant is valid for - defaults to the time the grant is created
+			"valid_from": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ValidateFunc:     validation.ValidateRFC3339TimeString,
+				DiffSuppressFunc: suppressIfTimestampsEqual,
+			},
+			// the end of the period the grant is valid for
+			"valid_to": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ValidateFunc:     validation.ValidateRFC3339TimeString,
+				DiffSuppressFunc: suppressIfTimestampsEqual,
+				ConflictsWith:    []string{"duration"},
+			},
+			// the length of the period the grant is valid for, e.g. '8h'
+			"duration": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ValidateFunc:  validateGrantDuration,
+				ConflictsWith: []string{"valid_to"},
+			},
+			// if set, an expired grant is deleted from Turbot by the next apply
+			"remove_expired": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			// if set and the workspace does not support grant validity periods, a grant with valid_to or duration is still created
+			// it is active immediately, and is only removed by the first apply after it expires
+			"terraform_enforced_expiry": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			// the time the grant expires, from valid_to or duration
+			"expires_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			// one of 'pending', 'active', 'expired' or 'removed'
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 		},
+		CustomizeDiff: resourceTurbotGrantCustomizeDiff,
+	}
+}
+
+// fail the plan of a new grant whose validity period the workspace cannot enforce,
+// and show an expired grant as a change, so the next apply removes it from Turbot
+func resourceTurbotGrantCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		// the validity period is checked when the grant is created if it is not known yet
+		for _, key := range []string{"valid_from", "valid_to", "duration", "remove_expired", "terraform_enforced_expiry"} {
+			if !d.NewValueKnown(key) {
+				return nil
+			}
+		}
+		_, _, err := grantValidityInput(d, meta, time.Now())
+		return err
+	}
+	if d.Get("status").(string) != grantStatusExpired || !d.Get("remove_expired").(bool) {
+		return nil
+	}
+	// if the grant is being replaced, the expired grant is deleted anyway
+	for _, key := range []string{"resource", "type", "level", "identity", "valid_from", "valid_to", "duration"} {
+		if d.HasChange(key) {
+			return nil
+		}
 	}
+	return d.SetNew("status", grantStatusRemoved)
 }
 
 func resourceTurbotGrantExists(d *schema.ResourceData, meta interface{}) (b bool, e error) {
+	// an expired grant which has been removed is kept in state, so it is not recreated
+	if d.Get("status").(string) == grantStatusRemoved {
+		return true, nil
+	}
 	client := meta.(*apiClient.Client)
 	id := d.Id()
-	return client.GrantExists(id)
+	exists, err := client.GrantExists(id)
+	if err != nil && apiClient.NotFoundError(err) && grantExpired(d, time.Now()) {
+		// Turbot deleted the grant when it expired - the read records this and logs a warning
+		return true, nil
+	}
+	return exists, err
 }
 
 func resourceTurbotGrantCreate(d *schema.ResourceData, meta interface{}) error {
@@ -98,6 +195,22 @@ func resourceTurbotGrantCreate(d *schema.ResourceData, meta interface{}) error {
 	permissionLevelAka := d.Get("level").(string)
 	// build map of Grant properties
 	input := mapFromResourceData(d, grantInputProperties)
+
+	validFrom, validTo, err := grantValidityInput(d, meta, time.Now())
+	if err != nil {
+		return err
+	}
+	if client.SupportsGrantExpiry() {
+		if validFrom != "" {
+			input["validFromTimestamp"] = validFrom
+		}
+		if validTo != "" {
+			input["validToTimestamp"] = validTo
+		}
+	} else if validTo != "" {
+		log.Printf("[WARN] the workspace does not support grant validity periods - the grant is active immediately, and is removed by the first apply after %s", validTo)
+	}
+
 	// create Grant returns turbot resource metadata containing the id
 	TurbotGrantMetadata, err := client.CreateGrant(input)
 	if err != nil {
@@ -120,16 +233,28 @@ func resourceTurbotGrantCreate(d *schema.ResourceData, meta interface{}) error {
 
 	// assign the id
 	d.SetId(TurbotGrantMetadata.Id)
+	d.Set("expires_at", validTo)
+	d.Set("status", grantStatus(validFrom, validTo, time.Now()))
 	return nil
 }
 
 func resourceTurbotGrantRead(d *schema.ResourceData, meta interface{}) error {
+	// an expired grant which has been removed no longer exists in Turbot
+	if d.Get("status").(string) == grantStatusRemoved {
+		return nil
+	}
 	client := meta.(*apiClient.Client)
 	id := d.Id()
 
 	Grant, err := client.ReadGrant(id)
 	if err != nil {
 		if apiClient.NotFoundError(err) {
+			// Turbot deletes grants when they expire - keep the grant in state, so it is not recreated
+			if grantExpired(d, time.Now()) {
+				log.Printf("[WARN] grant %s expired at %s and has been removed by Turbot - its status is now %q", id, d.Get("expires_at").(string), grantStatusRemoved)
+				d.Set("status", grantStatusRemoved)
+				return nil
+			}
 			// Grant was not found - clear id
 			d.SetId("")
 		}
@@ -141,6 +266,11 @@ func resourceTurbotGrantRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("type", Grant.PermissionTypeId)
 	d.Set("identity", Grant.Turbot.ProfileId)
 	d.Set("resource", Grant.Turbot.ResourceId)
+	// if the workspace supports grant validity periods, use the validity period of the grant
+	if Grant.ValidToTimestamp != "" {
+		d.Set("expires_at", Grant.ValidToTimestamp)
+	}
+	d.Set("status", grantStatus(d.Get("valid_from").(string), d.Get("expires_at").(string), time.Now()))
 
 	// set akas properties by loading resource and fetching the akas
 	if err := storeAkas(Grant.Turbot.ResourceId, "resource_akas", d, meta); err != nil {
@@ -155,13 +285,29 @@ func resourceTurbotGrantRead(d *schema.ResourceData, meta interface{}) error {
 	return storeAkas(Grant.PermissionLevelId, "permission_level_akas", d, meta)
 }
 
-func resourceTurbotGrantDelete(d *schema.ResourceData, meta interface{}) error {
+// all changes other than removing an expired grant force a new grant
+func resourceTurbotGrantUpdate(d *schema.ResourceData, meta interface{}) error {
+	if d.Get("status").(string) != grantStatusRemoved {
+		return nil
+	}
 	client := meta.(*apiClient.Client)
 	id := d.Id()
-	err := client.DeleteGrant(id)
-	if err != nil {
+	if err := client.DeleteGrant(id); err != nil && !apiClient.NotFoundError(err) {
 		return err
 	}
+	log.Printf("[INFO] removed expired grant %s", id)
+	return nil
+}
+
+func resourceTurbotGrantDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	id := d.Id()
+	// an expired grant which has been removed no longer exists in Turbot
+	if d.Get("status").(string) != grantStatusRemoved {
+		if err := client.DeleteGrant(id); err != nil {
+			return err
+		}
+	}
 
 	// clear the id to show we have deleted
 	d.SetId("")
@@ -170,8 +316,126 @@ func resourceTurbotGrantDelete(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceTurbotGrantImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := resolveGrantImportId(d, meta); err != nil {
+		return nil, err
+	}
+	// set the defaults, so an imported grant does not show a diff
+	d.Set("remove_expired", true)
+	d.Set("terraform_enforced_expiry", false)
 	if err := resourceTurbotGrantRead(d, meta); err != nil {
 		return nil, err
 	}
+	// the validity period of the grant is only available if the workspace supports it
+	d.Set("valid_to", d.Get("expires_at"))
 	return []*schema.ResourceData{d}, nil
 }
+
+// determine the validity period of a new grant
+// if duration is set, valid_to is calculated from valid_from, or from now if valid_from is not set
+func buildGrantValidity(validFrom, validTo, duration string, now time.Time) (string, string, error) {
+	start := now
+	if validFrom != "" {
+		var err error
+		if start, err = time.Parse(time.RFC3339, validFrom); err != nil {
+			return "", "", fmt.Errorf("invalid valid_from '%s': %s", validFrom, err.Error())
+		}
+	}
+	if duration != "" {
+		length, err := time.ParseDuration(duration)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid duration '%s': %s", duration, err.Error())
+		}
+		validTo = start.Add(length).UTC().Format(time.RFC3339)
+	}
+	if validTo == "" {
+		return validFrom, "", nil
+	}
+	end, err := time.Parse(time.RFC3339, validTo)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid valid_to '%s': %s", validTo, err.Error())
+	}
+	if !end.After(now) {
+		return "", "", fmt.Errorf("the grant would expire at %s, which is in the past", validTo)
+	}
+	if !end.After(start) {
+		return "", "", fmt.Errorf("the grant must expire after it becomes valid - valid_to %s is not after valid_from %s", validTo, start.UTC().Format(time.RFC3339))
+	}
+	return validFrom, validTo, nil
+}
+
+// determine the validity period of a new grant, failing if the workspace cannot enforce it
+// (d is either a ResourceData or ResourceDiff)
+func grantValidityInput(d interface {
+	Get(string) interface{}
+}, meta interface{}, now time.Time) (string, string, error) {
+	validFrom, validTo, err := buildGrantValidity(d.Get("valid_from").(string), d.Get("valid_to").(string), d.Get("duration").(string), now)
+	if err != nil {
+		return "", "", err
+	}
+	if meta.(*apiClient.Client).SupportsGrantExpiry() {
+		return validFrom, validTo, nil
+	}
+	if err := checkTerraformEnforcedValidity(validFrom, validTo, d.Get("terraform_enforced_expiry").(bool), d.Get("remove_expired").(bool), now); err != nil {
+		return "", "", err
+	}
+	return validFrom, validTo, nil
+}
+
+// if the workspace does not support grant validity periods, the grant is active as soon as it is created:
+// - a grant which is not valid until later cannot be created
+// - a grant which expires is only removed by Terraform, so this must be opted into with terraform_enforced_expiry
+func checkTerraformEnforcedValidity(validFrom, validTo string, terraformEnforcedExpiry, removeExpired bool, now time.Time) error {
+	if start, err := time.Parse(time.RFC3339, validFrom); err == nil && now.Before(start) {
+		return fmt.Errorf("the workspace does not support grant validity periods, so a grant with valid_from %s in the future would be active immediately. Create the grant once it should become valid", validFrom)
+	}
+	if validTo == "" {
+		return nil
+	}
+	if !terraformEnforcedExpiry {
+		return fmt.Errorf("the workspace does not support grant validity periods, so the grant would not expire at %s. Set terraform_enforced_expiry = true to create the grant anyway and remove it with the first apply after it expires", validTo)
+	}
+	if !removeExpired {
+		return fmt.Errorf("terraform_enforced_expiry requires remove_expired, as the workspace does not support grant validity periods and only Terraform removes the expired grant")
+	}
+	return nil
+}
+
+// the status of a grant at the given time
+func grantStatus(validFrom, expiresAt string, now time.Time) string {
+	if end, err := time.Parse(time.RFC3339, expiresAt); err == nil && !now.Before(end) {
+		return grantStatusExpired
+	}
+	if start, err := time.Parse(time.RFC3339, validFrom); err == nil && now.Before(start) {
+		return grantStatusPending
+	}
+	return grantStatusActive
+}
+
+func grantExpired(d *schema.ResourceData, now time.Time) bool {
+	return grantStatus(d.Get("valid_from").(string), d.Get("expires_at").(string), now) == grantStatusExpired
+}
+
+func validateGrantDuration(v interface{}, k string) (ws []string, errors []error) {
+	duration, err := time.ParseDuration(v.(string))
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%q: invalid duration '%s' - use a number and unit, e.g. '30m' or '8h'", k, v.(string)))
+		return
+	}
+	if duration <= 0 {
+		errors = append(errors, fmt.Errorf("%q: duration must be positive, got '%s'", k, v.(string)))
+	}
+	return
+}
+
+// Turbot may return a timestamp in a different format to the config, e.g. with milliseconds
+func suppressIfTimestampsEqual(k, old, new string, d *schema.ResourceData) bool {
+	oldTime, err := time.Parse(time.RFC3339, old)
+	if err != nil {
+		return false
+	}
+	newTime, err := time.Parse(time.RFC3339, new)
+	if err != nil {
+		return false
+	}
+	return oldTime.Equal(newTime)
+}
@@ -1,8 +1,15 @@
 package turbot
 
 import (
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestAccGrant_Basic(t *testing.T) {
@@ -27,6 +34,151 @@ func TestAccGrant_Basic(t *testing.T) {
 	})
 }
 
+func TestAccGrant_Duration(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(testAccCheckLocalGrantDestroy, testAccCheckActiveGrantDestroy),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGrantDurationConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckLocalGrantExists("turbot_grant.test_grant"),
+					resource.TestCheckResourceAttr(
+						"turbot_grant.test_grant", "duration", "1h"),
+					resource.TestCheckResourceAttr(
+						"turbot_grant.test_grant", "status", "active"),
+					resource.TestMatchResourceAttr(
+						"turbot_grant.test_grant", "expires_at", regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T`)),
+				),
+			},
+		},
+	})
+}
+
+func TestBuildGrantValidity(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	type test struct {
+		name          string
+		validFrom     string
+		validTo       string
+		duration      string
+		expectedFrom  string
+		expectedTo    string
+		expectedError string
+	}
+	tests := []test{
+		{"No validity period", "", "", "", "", "", ""},
+		{"Valid to", "", "2020-01-02T00:00:00Z", "", "", "2020-01-02T00:00:00Z", ""},
+		{"Duration from now", "", "", "8h", "", "2020-01-01T20:00:00Z", ""},
+		{"Duration from valid from", "2020-01-03T00:00:00Z", "", "30m", "2020-01-03T00:00:00Z", "2020-01-03T00:30:00Z", ""},
+		{"Valid to in the past", "", "2019-12-31T00:00:00Z", "", "", "", "which is in the past"},
+		{"Valid to before valid from", "2020-01-03T00:00:00Z", "2020-01-02T00:00:00Z", "", "", "", "must expire after it becomes valid"},
+	}
+	for _, test := range tests {
+		validFrom, validTo, err := buildGrantValidity(test.validFrom, test.validTo, test.duration, now)
+		if test.expectedError != "" {
+			if assert.NotNil(t, err, test.name) {
+				assert.Contains(t, err.Error(), test.expectedError, test.name)
+			}
+			continue
+		}
+		assert.Nil(t, err, test.name)
+		assert.Equal(t, test.expectedFrom, validFrom, test.name)
+		assert.Equal(t, test.expectedTo, validTo, test.name)
+	}
+}
+
+func TestCheckTerraformEnforcedValidity(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	type test struct {
+		name                    string
+		validFrom               string
+		validTo                 string
+		terraformEnforcedExpiry bool
+		removeExpired           bool
+		expectedError           string
+	}
+	tests := []test{
+		{"No validity period", "", "", false, true, ""},
+		{"Valid from in the past", "2020-01-01T00:00:00Z", "", false, true, ""},
+		{"Valid from in the future", "2020-01-01T13:00:00Z", "", true, true, "would be active immediately"},
+		{"Valid to without opt in", "", "2020-01-02T00:00:00Z", false, true, "Set terraform_enforced_expiry = true"},
+		{"Valid to with opt in", "", "2020-01-02T00:00:00Z", true, true, ""},
+		{"Opt in without remove_expired", "", "2020-01-02T00:00:00Z", true, false, "requires remove_expired"},
+	}
+	for _, test := range tests {
+		err := checkTerraformEnforcedValidity(test.validFrom, test.validTo, test.terraformEnforcedExpiry, test.removeExpired, now)
+		if test.expectedError == "" {
+			assert.Nil(t, err, test.name)
+		} else if assert.NotNil(t, err, test.name) {
+			assert.Contains(t, err.Error(), test.expectedError, test.name)
+		}
+	}
+}
+
+func TestGrantStatus(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, grantStatusActive, grantStatus("", "", now))
+	assert.Equal(t, grantStatusActive, grantStatus("2020-01-01T00:00:00Z", "2020-01-02T00:00:00.000Z", now))
+	assert.Equal(t, grantStatusPending, grantStatus("2020-01-01T13:00:00Z", "2020-01-02T00:00:00Z", now))
+	assert.Equal(t, grantStatusExpired, grantStatus("", "2020-01-01T12:00:00Z", now))
+}
+
+func TestValidateGrantDuration(t *testing.T) {
+	_, errors := validateGrantDuration("8h", "duration")
+	assert.Empty(t, errors)
+	_, errors = validateGrantDuration("8 hours", "duration")
+	assert.Len(t, errors, 1)
+	_, errors = validateGrantDuration("-1h", "duration")
+	assert.Len(t, errors, 1)
+}
+
+func TestSuppressIfTimestampsEqual(t *testing.T) {
+	assert.True(t, suppressIfTimestampsEqual("valid_to", "2020-01-02T00:00:00.000Z", "2020-01-02T00:00:00Z", nil))
+	assert.True(t, suppressIfTimestampsEqual("valid_to", "2020-01-02T01:00:00+01:00", "2020-01-02T00:00:00Z", nil))
+	assert.False(t, suppressIfTimestampsEqual("valid_to", "2020-01-02T00:00:00Z", "2020-01-03T00:00:00Z", nil))
+	assert.False(t, suppressIfTimestampsEqual("valid_to", "", "2020-01-03T00:00:00Z", nil))
+}
+
+func TestGrantCustomizeDiffRemovesExpiredGrant(t *testing.T) {
+	diff := func(state map[string]string, raw map[string]interface{}) *terraform.InstanceDiff {
+		c, err := config.NewRawConfig(raw)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		instanceState := &terraform.InstanceState{ID: "123", Attributes: state}
+		instanceDiff, err := schema.InternalMap(resourceTurbotGrant().Schema).Diff(instanceState, terraform.NewResourceConfig(c), resourceTurbotGrantCustomizeDiff, nil, true)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		return instanceDiff
+	}
+	raw := map[string]interface{}{"resource": "1", "type": "2", "level": "3", "identity": "4", "valid_to": "2020-01-02T00:00:00Z"}
+	state := map[string]string{"resource": "1", "type": "2", "level": "3", "identity": "4", "valid_to": "2020-01-02T00:00:00Z", "remove_expired": "true", "terraform_enforced_expiry": "false", "expires_at": "2020-01-02T00:00:00Z",
+		"resource_akas.#": "0", "permission_type_akas.#": "0", "permission_level_akas.#": "0", "identity_akas.#": "0"}
+
+	// an active grant has no diff
+	state["status"] = grantStatusActive
+	assert.Nil(t, diff(state, raw))
+
+	// an expired grant is removed in place
+	state["status"] = grantStatusExpired
+	instanceDiff := diff(state, raw)
+	if assert.NotNil(t, instanceDiff) {
+		assert.False(t, instanceDiff.RequiresNew())
+		assert.Equal(t, grantStatusRemoved, instanceDiff.Attributes["status"].New)
+	}
+
+	// unless remove_expired is disabled
+	raw["remove_expired"] = false
+	instanceDiff = diff(state, raw)
+	if assert.NotNil(t, instanceDiff) {
+		_, ok := instanceDiff.Attributes["status"]
+		assert.False(t, ok)
+	}
+}
+
 // configs
 func testAccGrantConfig() string {
 	return `
@@ -63,3 +215,10 @@ resource "turbot_grant" "test_grant" {
 }
 `
 }
+
+func testAccGrantDurationConfig() string {
+	return strings.Replace(testAccGrantConfig(), `identity          = turbot_profile.test_profile.id`, `identity          = turbot_profile.test_profile.id
+	duration          = "1h"
+	# the test workspace may not support grant validity periods
+	terraform_enforced_expiry = true`, 1)
+}
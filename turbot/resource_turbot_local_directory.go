@@ -36,6 +36,17 @@ func resourceTurbotLocalDirectory() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			// the version of the directory when it was last read - updates fail if it has been modified since
+			"version_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			// if set, destroying or replacing the directory fails
+			"deletion_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			"title": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -77,12 +88,16 @@ func resourceTurbotLocalDirectoryCreate(d *schema.ResourceData, meta interface{}
 	client := meta.(*apiClient.Client)
 	// build mutation input
 	input := mapFromResourceData(d, localDirectoryInputProperties)
+	applyDefaultTags(input, meta)
 	data := mapFromResourceData(d, localDirectoryDataProperties)
 	// set computed properties
 	data["status"] = "Active"
 	data["directoryType"] = "local"
 	input["data"] = data
 
+	// record the Terraform configuration which owns the local directory
+	input["terraform"] = ownershipMetadata("turbot_local_directory", meta)
+
 	// do create
 	localDirectory, err := client.CreateLocalDirectory(input)
 	if err != nil {
@@ -122,6 +137,7 @@ func resourceTurbotLocalDirectoryRead(d *schema.ResourceData, meta interface{})
 	d.Set("title", localDirectory.Title)
 	d.Set("status", localDirectory.Status)
 	d.Set("directory_type", localDirectory.DirectoryType)
+	d.Set("version_id", localDirectory.Turbot.VersionId)
 	// set parent_akas property by loading resource and fetching the akas
 	return storeAkas(localDirectory.Turbot.ParentId, "parent_akas", d, meta)
 }
@@ -130,13 +146,16 @@ func resourceTurbotLocalDirectoryUpdate(d *schema.ResourceData, meta interface{}
 	client := meta.(*apiClient.Client)
 	// build mutation payload
 	input := mapFromResourceData(d, localDirectoryInputProperties)
+	applyDefaultTags(input, meta)
 	input["data"] = mapFromResourceData(d, localDirectoryDataProperties)
 	input["id"] = d.Id()
+	// Turbot rejects the update if the directory has been modified outside Terraform since it was last read
+	setVersionPrecondition(input, d)
 
 	// do update
 	localDirectory, err := client.UpdateLocalDirectory(input)
 	if err != nil {
-		return err
+		return versionConflictError("directory", d, err)
 	}
 
 	// assign properties coming back from update graphQl API
@@ -144,11 +163,15 @@ func resourceTurbotLocalDirectoryUpdate(d *schema.ResourceData, meta interface{}
 	d.Set("title", localDirectory.Title)
 	d.Set("status", localDirectory.Status)
 	d.Set("directory_type", localDirectory.DirectoryType)
+	d.Set("version_id", localDirectory.Turbot.VersionId)
 	// set parent_akas property by loading resource and fetching the akas
 	return storeAkas(localDirectory.Turbot.ParentId, "parent_akas", d, meta)
 }
 
 func resourceTurbotLocalDirectoryDelete(d *schema.ResourceData, meta interface{}) error {
+	if err := checkDeletionProtection(d, "turbot_local_directory"); err != nil {
+		return err
+	}
 	client := meta.(*apiClient.Client)
 	id := d.Id()
 	err := client.DeleteResource(id)
@@ -162,6 +185,11 @@ func resourceTurbotLocalDirectoryDelete(d *schema.ResourceData, meta interface{}
 }
 
 func resourceTurbotLocalDirectoryImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := resolveImportId(d, meta); err != nil {
+		return nil, err
+	}
+	// set the default, so an imported resource does not show a diff
+	d.Set("deletion_protection", false)
 	if err := resourceTurbotLocalDirectoryRead(d, meta); err != nil {
 		return nil, err
 	}
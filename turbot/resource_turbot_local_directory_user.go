@@ -36,6 +36,11 @@ func resourceTurbotLocalDirectoryUser() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			// the version of the user when it was last read - updates fail if it has been modified since
+			"version_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"title": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -94,11 +99,15 @@ func resourceTurbotLocalDirectoryUserCreate(d *schema.ResourceData, meta interfa
 
 	// build mutation input
 	input := mapFromResourceData(d, localDirectoryUserInputProperties)
+	applyDefaultTags(input, meta)
 	data := mapFromResourceData(d, localDirectoryUserDataProperties)
 	// set computed properties
 	data["status"] = "Active"
 	input["data"] = data
 
+	// record the Terraform configuration which owns the user
+	input["terraform"] = ownershipMetadata("turbot_local_directory_user", meta)
+
 	// do create
 	localDirectoryUser, err := client.CreateLocalDirectoryUser(input)
 	if err != nil {
@@ -128,13 +137,16 @@ func resourceTurbotLocalDirectoryUserUpdate(d *schema.ResourceData, meta interfa
 	client := meta.(*apiClient.Client)
 	// build mutation payload
 	input := mapFromResourceData(d, localDirectoryUserInputProperties)
+	applyDefaultTags(input, meta)
 	input["data"] = mapFromResourceData(d, localDirectoryUserDataProperties)
 	input["id"] = d.Id()
+	// Turbot rejects the update if the user has been modified outside Terraform since it was last read
+	setVersionPrecondition(input, d)
 
 	// do update
 	localDirectoryUser, err := client.UpdateLocalDirectoryUserResource(input)
 	if err != nil {
-		return err
+		return versionConflictError("user", d, err)
 	}
 	d.Set("parent", localDirectoryUser.Parent)
 	d.Set("title", localDirectoryUser.Title)
@@ -145,6 +157,7 @@ func resourceTurbotLocalDirectoryUserUpdate(d *schema.ResourceData, meta interfa
 	d.Set("middle_name", localDirectoryUser.MiddleName)
 	d.Set("family_name", localDirectoryUser.FamilyName)
 	d.Set("picture", localDirectoryUser.Picture)
+	d.Set("version_id", localDirectoryUser.Turbot.VersionId)
 	// set parent_akas property by loading parent resource and fetching the akas
 	return storeAkas(localDirectoryUser.Turbot.ParentId, "parent_akas", d, meta)
 }
@@ -161,6 +174,7 @@ func resourceTurbotLocalDirectoryUserRead(d *schema.ResourceData, meta interface
 		return err
 	}
 	// assign results back into ResourceData
+	d.Set("version_id", localDirectoryUser.Turbot.VersionId)
 	// set parent_akas property by loading parent resource and fetching the akas
 	if err := storeAkas(localDirectoryUser.Turbot.ParentId, "parent_akas", d, meta); err != nil {
 		return err
@@ -191,6 +205,9 @@ func resourceTurbotLocalDirectoryUserDelete(d *schema.ResourceData, meta interfa
 }
 
 func resourceTurbotLocalDirectoryUserImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := resolveImportId(d, meta); err != nil {
+		return nil, err
+	}
 	if err := resourceTurbotLocalDirectoryUserRead(d, meta); err != nil {
 		return nil, err
 	}
@@ -42,6 +42,18 @@ func resourceTurbotMod() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			// if set, destroying or replacing the mod fails
+			"deletion_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			// if set, the mod is uninstalled even if other mods depend on it or resources of its types exist
+			"force": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			"uri": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -72,12 +84,43 @@ func resourceTurbotMod() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			// if set, create adopts an existing installation of the mod rather than failing
+			"adopt_existing": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			// the id of the existing mod adopted by create
+			"adopted_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 		},
 		CustomizeDiff: resourceTurbotModCustomizeDiff,
 	}
 }
 
 func resourceTurbotModCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if err := customizeDiffDeletionProtection(d, "turbot_mod", []string{"org", "mod"}); err != nil {
+		return err
+	}
+	err := customizeDiffAdoptedId(d, meta, []string{"org", "mod"}, func() (string, error) {
+		client := meta.(*apiClient.Client)
+		mod, err := client.ReadResource(buildModAka(d.Get("org").(string), d.Get("mod").(string)), nil)
+		if err != nil {
+			if apiClient.NotFoundError(err) {
+				return "", nil
+			}
+			return "", err
+		}
+		// fail the plan if the mod is managed by another Terraform configuration
+		if err := checkOwnership("mod", mod.Turbot.Id, mod.Turbot.Terraform, meta); err != nil {
+			return "", err
+		}
+		return mod.Turbot.Id, nil
+	})
+	if err != nil {
+		return err
+	}
 
 	versionCurrent := d.Get("version_current").(string)
 	var versionLatest string
@@ -101,10 +144,27 @@ func resourceTurbotModCustomizeDiff(d *schema.ResourceDiff, meta interface{}) er
 		if err := d.SetNew("version_current", versionLatest); err != nil {
 			return err
 		}
+		if d.Id() != "" && versionLatest != "" {
+			warnDroppedResourceTypes(buildModAka(d.Get("org").(string), d.Get("mod").(string)), versionLatest, meta)
+		}
 	}
 	return nil
 }
 
+// warn if changing the installed version would remove resource types which still have resources
+// NOTE: this only warns - failing to check does not fail the plan
+func warnDroppedResourceTypes(modUri, version string, meta interface{}) {
+	client := meta.(*apiClient.Client)
+	dropped, err := client.ReadDroppedResourceTypesInUse(modUri, version)
+	if err != nil {
+		log.Printf("[WARN] could not check the resource types provided by version %s of mod %s: %s", version, modUri, err.Error())
+		return
+	}
+	for _, resourceType := range dropped {
+		log.Printf("[WARN] installing version %s of mod %s removes resource type %s, which has resources", version, modUri, resourceType)
+	}
+}
+
 func resourceTurbotModExists(d *schema.ResourceData, meta interface{}) (b bool, e error) {
 	client := meta.(*apiClient.Client)
 	id := d.Id()
@@ -122,6 +182,9 @@ func resourceTurbotModInstall(d *schema.ResourceData, meta interface{}) error {
 	if err == nil {
 		// if there is no error, the mod is already installed
 		id := mod.Turbot.Id
+		if adoptExistingEnabled(d.Get("adopt_existing").(bool), meta) {
+			return adoptMod(d, meta, &mod.Turbot)
+		}
 		return fmt.Errorf("mod %s is already installed ( id: %s ). To manage this mod using Terraform, import the mod using command 'terraform import <resource_address> <id>'", modAka, id)
 	}
 	if !apiClient.NotFoundError(err) {
@@ -129,7 +192,39 @@ func resourceTurbotModInstall(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
-	return modInstall(d, meta)
+	d.Set("adopted_id", "")
+	if err := modInstall(d, meta); err != nil {
+		return err
+	}
+	// record the Terraform configuration which owns the mod
+	// NOTE: the install mutation does not accept the metadata, so it is set once the mod is installed
+	return stampOwnership(d.Id(), "turbot_mod", meta)
+}
+
+// take ownership of an installed mod, only reinstalling if the installed version does not satisfy the version requirement
+func adoptMod(d *schema.ResourceData, meta interface{}, mod *apiClient.TurbotResourceMetadata) error {
+	client := meta.(*apiClient.Client)
+	id := mod.Id
+	log.Printf("[INFO] adopting existing mod %s", id)
+	if err := claimOwnership(mod, "turbot_mod", meta); err != nil {
+		return err
+	}
+	d.SetId(id)
+	d.Set("adopted_id", id)
+
+	installedVersion, _, err := getInstalledModVersion(id, client)
+	if err != nil {
+		return err
+	}
+	targetVersion, err := getLatestCompatibleVersion(d.Get("org").(string), d.Get("mod").(string), d.Get("version").(string), meta)
+	if err != nil {
+		return err
+	}
+	if installedVersion != targetVersion {
+		log.Printf("[INFO] adopted mod %s has version %s, installing version %s", id, installedVersion, targetVersion)
+		return modInstall(d, meta)
+	}
+	return resourceTurbotModRead(d, meta)
 }
 
 func resourceTurbotModUpdate(d *schema.ResourceData, meta interface{}) error {
@@ -203,8 +298,16 @@ func resourceTurbotModRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceTurbotModUninstall(d *schema.ResourceData, meta interface{}) error {
+	if err := checkDeletionProtection(d, "turbot_mod"); err != nil {
+		return err
+	}
 	client := meta.(*apiClient.Client)
 	id := d.Id()
+	if !d.Get("force").(bool) {
+		if err := checkModUninstall(d, meta); err != nil {
+			return err
+		}
+	}
 	err := client.UninstallMod(id)
 	if err != nil {
 		return err
@@ -216,7 +319,31 @@ func resourceTurbotModUninstall(d *schema.ResourceData, meta interface{}) error
 	return nil
 }
 
+// fail if other mods depend on the mod, or if resources of its types exist
+func checkModUninstall(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	modUri := buildModAka(d.Get("org").(string), d.Get("mod").(string))
+	blockers, err := client.CheckModUninstall(modUri)
+	if err != nil {
+		return err
+	}
+	return modUninstallBlockedError(modUri, blockers)
+}
+
+func modUninstallBlockedError(modUri string, blockers *apiClient.ModUninstallBlockers) error {
+	if blockers.Empty() {
+		return nil
+	}
+	return fmt.Errorf("cannot uninstall mod %s:\n  - %s\nRemove these first, or set force = true to uninstall the mod anyway", modUri, strings.Join(blockers.Descriptions(), "\n  - "))
+}
+
 func resourceTurbotModImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := resolveImportId(d, meta); err != nil {
+		return nil, err
+	}
+	// set the defaults, so an imported resource does not show a diff
+	d.Set("deletion_protection", false)
+	d.Set("force", false)
 	if err := resourceTurbotModRead(d, meta); err != nil {
 		return nil, err
 	}
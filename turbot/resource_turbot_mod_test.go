@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
 	"github.com/terraform-providers/terraform-provider-turbot/apiClient"
 	"testing"
 )
@@ -123,6 +124,16 @@ func TestAccMod_Basic(t *testing.T) {
 	})
 }
 
+// the blocker descriptions are tested in apiClient - this checks how they are formatted in the error
+func TestModUninstallBlockedError(t *testing.T) {
+	assert.Nil(t, modUninstallBlockedError("tmod:@turbot/aws", &apiClient.ModUninstallBlockers{}))
+
+	err := modUninstallBlockedError("tmod:@turbot/aws", &apiClient.ModUninstallBlockers{DependentMods: []string{"tmod:@turbot/aws-s3"}})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "cannot uninstall mod tmod:@turbot/aws:\n  - mod tmod:@turbot/aws-s3 depends on this mod\nRemove these first, or set force = true to uninstall the mod anyway", err.Error())
+	}
+}
+
 // configs
 func testAccMod_v5_0_0_Config() string {
 	return `
@@ -6,6 +6,7 @@ import (
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/terraform-providers/terraform-provider-turbot/apiClient"
 	"github.com/terraform-providers/terraform-provider-turbot/helpers"
+	"log"
 )
 
 var policySettingInputProperties = []interface{}{"value", "precedence", "template", "template_input", "note", "valid_from_timestamp", "valid_to_timestamp", "type", "resource"}
@@ -43,6 +44,11 @@ func resourceTurbotPolicySetting() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			// the version of the policy setting when it was last read - updates fail if it has been modified since
+			"version_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"value": {
 				Type:             schema.TypeString,
 				Optional:         true,
@@ -94,10 +100,36 @@ func resourceTurbotPolicySetting() *schema.Resource {
 				ForceNew: true,
 				Optional: true,
 			},
+			// if set, create adopts an existing policy setting rather than failing
+			"adopt_existing": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			// the id of the existing policy setting adopted by create
+			"adopted_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 		},
+		CustomizeDiff: resourceTurbotPolicySettingCustomizeDiff,
 	}
 }
 
+func resourceTurbotPolicySettingCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	return customizeDiffAdoptedId(d, meta, []string{"type", "resource"}, func() (string, error) {
+		client := meta.(*apiClient.Client)
+		existingSetting, err := client.FindPolicySetting(d.Get("type").(string), d.Get("resource").(string))
+		if err != nil || existingSetting.Value == nil {
+			return "", err
+		}
+		// fail the plan if the policy setting is managed by another Terraform configuration
+		if err := checkOwnership("policy setting", existingSetting.Turbot.Id, existingSetting.Turbot.Terraform, meta); err != nil {
+			return "", err
+		}
+		return existingSetting.Turbot.Id, nil
+	})
+}
+
 func resourceTurbotPolicySettingExists(d *schema.ResourceData, meta interface{}) (b bool, e error) {
 	// Exists - This is called to verify a resource still exists. It is called prior to Read,
 	// and lowers the burden of Read to be able to assume the resource exists.
@@ -125,6 +157,9 @@ func resourceTurbotPolicySettingCreate(d *schema.ResourceData, meta interface{})
 		return err
 	}
 	if existingSetting.Value != nil {
+		if adoptExistingEnabled(d.Get("adopt_existing").(bool), meta) {
+			return adoptPolicySetting(d, meta, &existingSetting.Turbot)
+		}
 		return fmt.Errorf("A policy setting for policy type: '%s', resource: '%s' already exists ( id: %s ). To manage the existing setting using Terraform, import it using command 'terraform import <resource_address> <id>'",
 			policyTypeUri, resourceAka, existingSetting.Turbot.Id)
 	}
@@ -137,6 +172,9 @@ func resourceTurbotPolicySettingCreate(d *schema.ResourceData, meta interface{})
 	// 1) pass value as 'value'
 	// 2) pass value as 'valueSource'. update d.value to be the yaml parsed version of 'value'
 	input := mapFromResourceData(d, policySettingInputProperties)
+	applyDefaultPolicyNote(input, meta)
+	// record the Terraform configuration which owns the policy setting
+	input["terraform"] = ownershipMetadata("turbot_policy_setting", meta)
 	policySetting, err := client.CreatePolicySetting(input)
 	if err != nil {
 		if !apiClient.FailedValidationError(err) {
@@ -166,15 +204,40 @@ func resourceTurbotPolicySettingCreate(d *schema.ResourceData, meta interface{})
 	d.Set("precedence", policySetting.Precedence)
 	d.Set("template", policySetting.Template)
 	d.Set("template_input", policySetting.TemplateInput)
-	d.Set("note", policySetting.Note)
+	d.Set("note", removeDefaultPolicyNote(policySetting.Note, d, meta))
 	d.Set("valid_from_timestamp", policySetting.ValidFromTimestamp)
 	d.Set("valid_to_timestamp", policySetting.ValidToTimestamp)
+	d.Set("version_id", policySetting.Turbot.VersionId)
 	// assign the id
 	d.SetId(policySetting.Turbot.Id)
+	d.Set("adopted_id", "")
 
 	return nil
 }
 
+// take ownership of an existing policy setting, updating it to match the config
+func adoptPolicySetting(d *schema.ResourceData, meta interface{}, setting *apiClient.TurbotPolicyMetadata) error {
+	client := meta.(*apiClient.Client)
+	id := setting.Id
+	log.Printf("[INFO] adopting existing policy setting %s", id)
+	if err := checkOwnership("policy setting", id, setting.Terraform, meta); err != nil {
+		return err
+	}
+	if _, err := client.UpdatePolicySetting(map[string]interface{}{
+		"id":        id,
+		"terraform": ownershipMetadata("turbot_policy_setting", meta),
+	}); err != nil {
+		return err
+	}
+	d.SetId(id)
+	if err := resourceTurbotPolicySettingUpdate(d, meta); err != nil {
+		return err
+	}
+	d.Set("adopted_id", id)
+	// set akas properties by loading resource and fetching the akas
+	return storeAkas(d.Get("resource").(string), "resource_akas", d, meta)
+}
+
 func resourceTurbotPolicySettingRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*apiClient.Client)
 	id := d.Id()
@@ -199,9 +262,10 @@ func resourceTurbotPolicySettingRead(d *schema.ResourceData, meta interface{}) e
 	d.Set("precedence", setting.Precedence)
 	d.Set("template", setting.Template)
 	d.Set("template_input", setting.TemplateInput)
-	d.Set("note", setting.Note)
+	d.Set("note", removeDefaultPolicyNote(setting.Note, d, meta))
 	d.Set("valid_from_timestamp", setting.ValidFromTimestamp)
 	d.Set("valid_to_timestamp", setting.ValidToTimestamp)
+	d.Set("version_id", setting.Turbot.VersionId)
 
 	return nil
 }
@@ -218,11 +282,19 @@ func resourceTurbotPolicySettingUpdate(d *schema.ResourceData, meta interface{})
 	// 1) pass value as 'value'
 	// 2) pass value as 'valueSource'. update d.value to be the yaml parsed version of 'value'
 	input := mapFromResourceData(d, getPolicySettingUpdateProperties())
+	applyDefaultPolicyNote(input, meta)
 	input["id"] = id
+	// Turbot rejects the update if the policy setting has been modified outside Terraform since it was last read
+	setVersionPrecondition(input, d)
 
 	policySetting, err := client.UpdatePolicySetting(input)
 	if err != nil {
-		if !apiClient.FailedValidationError(err) {
+		// the policy setting still exists, so keep it in state
+		if apiClient.ConflictError(err) {
+			return versionConflictError("policy setting", d, err)
+		}
+		// if the value has been cleared there is no value source to try
+		if _, ok := input["value"].(string); !ok || !apiClient.FailedValidationError(err) {
 			d.SetId("")
 			return err
 		}
@@ -242,9 +314,10 @@ func resourceTurbotPolicySettingUpdate(d *schema.ResourceData, meta interface{})
 	d.Set("precedence", policySetting.Precedence)
 	d.Set("template", policySetting.Template)
 	d.Set("template_input", policySetting.TemplateInput)
-	d.Set("note", policySetting.Note)
+	d.Set("note", removeDefaultPolicyNote(policySetting.Note, d, meta))
 	d.Set("valid_from_timestamp", policySetting.ValidFromTimestamp)
 	d.Set("valid_to_timestamp", policySetting.ValidToTimestamp)
+	d.Set("version_id", policySetting.Turbot.VersionId)
 	return nil
 }
 
@@ -271,6 +344,9 @@ func resourceTurbotPolicySettingDelete(d *schema.ResourceData, meta interface{})
 }
 
 func resourceTurbotPolicySettingImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := resolvePolicySettingImportId(d, meta); err != nil {
+		return nil, err
+	}
 	if err := resourceTurbotPolicySettingRead(d, meta); err != nil {
 		return nil, err
 	}
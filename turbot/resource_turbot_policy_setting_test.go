@@ -58,6 +58,18 @@ func TestAccPolicySetting_String(t *testing.T) {
 						"turbot_policy_setting.test_policy", "precedence", "RECOMMENDED"),
 				),
 			},
+			{
+				// removing the template clears it
+				Config: testAccPolicySettingStringConfig(stringPolicyType, "testValue", "RECOMMENDED"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"turbot_policy_setting.test_policy", "value", "testValue"),
+					resource.TestCheckResourceAttr(
+						"turbot_policy_setting.test_policy", "template", ""),
+					resource.TestCheckResourceAttr(
+						"turbot_policy_setting.test_policy", "template_input", ""),
+				),
+			},
 		},
 	})
 }
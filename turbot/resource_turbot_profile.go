@@ -36,6 +36,11 @@ func resourceTurbotProfile() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			// the version of the profile when it was last read - updates fail if it has been modified since
+			"version_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"title": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -101,6 +106,9 @@ func resourceTurbotProfileCreate(d *schema.ResourceData, meta interface{}) error
 	input := mapFromResourceData(d, profileInputProperties)
 	input["data"] = mapFromResourceData(d, profileDataProperties)
 
+	// record the Terraform configuration which owns the profile
+	input["terraform"] = ownershipMetadata("turbot_profile", meta)
+
 	// do create
 	profile, err := client.CreateProfile(input)
 	if err != nil {
@@ -145,6 +153,7 @@ func resourceTurbotProfileRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("given_name", profile.GivenName)
 	d.Set("family_name", profile.FamilyName)
 	d.Set("directory_pool_id", profile.DirectoryPoolId)
+	d.Set("version_id", profile.Turbot.VersionId)
 	/// set parent_akas property by loading resource and fetching the akas
 	return storeAkas(profile.Turbot.ParentId, "parent_akas", d, meta)
 }
@@ -155,11 +164,13 @@ func resourceTurbotProfileUpdate(d *schema.ResourceData, meta interface{}) error
 	input := mapFromResourceData(d, profileInputProperties)
 	input["data"] = mapFromResourceData(d, profileDataProperties)
 	input["id"] = d.Id()
+	// Turbot rejects the update if the profile has been modified outside Terraform since it was last read
+	setVersionPrecondition(input, d)
 
 	// do create
 	profile, err := client.UpdateProfile(input)
 	if err != nil {
-		return err
+		return versionConflictError("profile", d, err)
 	}
 
 	// assign results back into ResourceData
@@ -170,6 +181,7 @@ func resourceTurbotProfileUpdate(d *schema.ResourceData, meta interface{}) error
 	d.Set("given_name", profile.GivenName)
 	d.Set("family_name", profile.FamilyName)
 	d.Set("directory_pool_id", profile.DirectoryPoolId)
+	d.Set("version_id", profile.Turbot.VersionId)
 	// set parent_akas property by loading resource and fetching the akas
 	return storeAkas(profile.Turbot.ParentId, "parent_akas", d, meta)
 }
@@ -188,6 +200,9 @@ func resourceTurbotProfileDelete(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceTurbotProfileImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := resolveImportId(d, meta); err != nil {
+		return nil, err
+	}
 	if err := resourceTurbotProfileRead(d, meta); err != nil {
 		return nil, err
 	}
@@ -3,8 +3,10 @@ package turbot
 import (
 	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/terraform-providers/terraform-provider-turbot/apiClient"
 	"github.com/terraform-providers/terraform-provider-turbot/helpers"
+	"reflect"
 )
 
 var resourceProperties = []interface{}{"parent", "type", "tags", "akas"}
@@ -24,6 +26,7 @@ func resourceTurbotResource() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: resourceTurbotResourceImport,
 		},
+		CustomizeDiff: resourceTurbotResourceCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 			// aka of the parent resource
 			"parent": {
@@ -41,16 +44,43 @@ func resourceTurbotResource() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			// the version of the resource when it was last read - updates fail if it has been modified since
+			"version_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"type": {
 				Type:     schema.TypeString,
 				Required: true,
 				ForceNew: true,
 			},
+			// one of data (JSON) or data_yaml must be set
 			"data": {
 				Type:             schema.TypeString,
-				Required:         true,
+				Optional:         true,
+				ConflictsWith:    []string{"data_yaml"},
 				DiffSuppressFunc: suppressIfDataMatches,
 			},
+			"data_yaml": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressIfYamlMatches,
+			},
+			// the top level properties of the data, with non string values json encoded - this gives a per-property plan diff
+			"data_properties": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			// if "strict", the full resource data is read, so properties added outside Terraform are reported as a diff
+			"data_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "default",
+				ValidateFunc: validation.StringInSlice([]string{"default", "strict"}, false),
+			},
 			"metadata": {
 				Type:             schema.TypeString,
 				Optional:         true,
@@ -85,11 +115,14 @@ func resourceTurbotResourceCreate(d *schema.ResourceData, meta interface{}) erro
 	var err error
 
 	// build input map to pass to mutation
-	input, err := buildResourceInput(d, resourceProperties)
+	input, err := buildResourceInput(d, resourceProperties, meta)
 	if err != nil {
 		return err
 	}
 
+	// record the Terraform configuration which owns the resource
+	input["terraform"] = ownershipMetadata("turbot_resource", meta)
+
 	turbotMetadata, err := client.CreateResource(input)
 	if err != nil {
 		return err
@@ -101,8 +134,9 @@ func resourceTurbotResourceCreate(d *schema.ResourceData, meta interface{}) erro
 	}
 	// assign the id
 	d.SetId(turbotMetadata.Id)
-	// save the formatted data: this is to ensure the acceptance tests behave in a consistent way regardless of the ordering of the json data
-	d.Set("data", helpers.FormatJson(d.Get("data").(string)))
+	if err := storeResourceData(d, input["data"].(map[string]interface{})); err != nil {
+		return err
+	}
 	if metadata, ok := d.GetOk("metadata"); ok {
 		d.Set("metadata", helpers.FormatJson(metadata.(string)))
 	}
@@ -110,14 +144,22 @@ func resourceTurbotResourceCreate(d *schema.ResourceData, meta interface{}) erro
 }
 
 func resourceTurbotResourceRead(d *schema.ResourceData, meta interface{}) error {
+	return readTurbotResource(d, meta, d.Get("data_mode").(string) == "strict")
+}
+
+// if strict is true, the full resource data is read, otherwise only the properties in the data are read
+func readTurbotResource(d *schema.ResourceData, meta interface{}, strict bool) error {
 	client := meta.(*apiClient.Client)
 	id := d.Id()
 
 	// build required properties from data.
 	// properties is a map of property name -> property path
-	properties, err := helpers.PropertyMapFromJson(d.Get("data").(string))
-
-	if err != nil {
+	var properties map[string]string
+	var err error
+	if strict {
+		// read the full resource data by passing an empty string as the property path
+		properties = map[string]string{"data": ""}
+	} else if properties, err = resourceDataProperties(d); err != nil {
 		return fmt.Errorf("error retrieving properties from resource data: %s", err.Error())
 	}
 
@@ -130,41 +172,77 @@ func resourceTurbotResourceRead(d *schema.ResourceData, meta interface{}) error
 		return err
 	}
 
-	// rebuild data from the resource
-	data, err := helpers.MapToJsonString(resource.Data)
-	if err != nil {
-		return fmt.Errorf("error building resource data: %s", err.Error())
+	resourceData := resource.Data
+	if strict {
+		resourceData = fullResourceData(resource)
 	}
 
 	// assign results back into ResourceData
 
+	d.Set("version_id", resource.Turbot.VersionId)
 	// set parent_akas property by loading resource and fetching the akas
 	if err := storeAkas(resource.Turbot.ParentId, "parent_akas", d, meta); err != nil {
 		return err
 	}
 	d.Set("parent", resource.Turbot.ParentId)
-	d.Set("data", data)
+	// rebuild data from the resource
+	if err := storeResourceData(d, resourceData); err != nil {
+		return fmt.Errorf("error building resource data: %s", err.Error())
+	}
+	// metadata is stored in turbot.custom
+	if len(resource.Turbot.Custom) > 0 {
+		metadata, err := helpers.MapToJsonString(resource.Turbot.Custom)
+		if err != nil {
+			return fmt.Errorf("error building resource metadata: %s", err.Error())
+		}
+		d.Set("metadata", metadata)
+	} else {
+		d.Set("metadata", "")
+	}
+	// tags and akas are read back so that changes made outside Terraform are detected
+	d.Set("tags", removeDefaultTags(filterIgnoredTags(resource.Turbot.Tags, client.IgnoreTags), d, meta))
+	// Turbot adds its own akas to the resource, so only the configured akas are read back
+	d.Set("akas", configuredAkas(d.Get("akas").([]interface{}), resource.Turbot.Akas))
 	return nil
 }
 
+// return the configured akas which the resource still has - a configured aka removed outside Terraform is reported as a diff
+func configuredAkas(configured []interface{}, akas []string) []string {
+	var result []string
+	for _, aka := range configured {
+		if helpers.SliceContains(akas, aka.(string)) {
+			result = append(result, aka.(string))
+		}
+	}
+	return result
+}
+
 func resourceTurbotResourceUpdate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*apiClient.Client)
 	// build input map to pass to mutation
-	input, err := buildResourceInput(d, getResourceUpdateProperties())
+	input, err := buildResourceInput(d, getResourceUpdateProperties(), meta)
 	if err != nil {
 		return err
 	}
 	input["id"] = d.Id()
+	// Turbot rejects the update if the resource has been modified outside Terraform since it was last read
+	setVersionPrecondition(input, d)
+	// if the metadata has been removed from the config, clear it
+	if _, ok := input["metadata"]; !ok && d.HasChange("metadata") {
+		input["metadata"] = map[string]interface{}{}
+	}
 
 	turbotMetadata, err := client.UpdateResource(input)
 	if err != nil {
+		return versionConflictError("resource", d, err)
+	}
+	if err := storeResourceData(d, input["data"].(map[string]interface{})); err != nil {
 		return err
 	}
-	// save the formatted data: this is to ensure the acceptance tests behave in a consistent way regardless of the ordering of the json data
-	d.Set("data", helpers.FormatJson(d.Get("data").(string)))
 	if metadata, ok := d.GetOk("metadata"); ok {
 		d.Set("metadata", helpers.FormatJson(metadata.(string)))
 	}
+	d.Set("version_id", turbotMetadata.VersionId)
 	// set parent_akas property by loading resource and fetching the akas
 	return storeAkas(turbotMetadata.ParentId, "parent_akas", d, meta)
 }
@@ -184,19 +262,112 @@ func resourceTurbotResourceDelete(d *schema.ResourceData, meta interface{}) erro
 }
 
 func resourceTurbotResourceImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	if err := resourceTurbotResourceRead(d, meta); err != nil {
+	if err := resolveImportId(d, meta); err != nil {
+		return nil, err
+	}
+	// there is no data in the state to build the properties from, so read the full resource data
+	if err := readTurbotResource(d, meta, true); err != nil {
 		return nil, err
 	}
+	// data_mode is not stored in Turbot - set the default so the imported state matches a config which omits it
+	d.Set("data_mode", "default")
 	return []*schema.ResourceData{d}, nil
 }
 
-func buildResourceInput(d *schema.ResourceData, properties []interface{}) (map[string]interface{}, error) {
+// extract the full resource data from a resource read using the "data" property
+// NOTE: remove the 'turbot' property as this is not part of the resource data
+func fullResourceData(resource *apiClient.Resource) map[string]interface{} {
+	data, ok := resource.Data["data"].(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{}
+	}
+	delete(data, "turbot")
+	return data
+}
+
+// populate data_properties in the plan, so the diff shows which properties have changed
+func resourceTurbotResourceCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("data") || !d.NewValueKnown("data_yaml") {
+		return d.SetNewComputed("data_properties")
+	}
+	data, err := resourceDataMap(d)
+	if err != nil {
+		return err
+	}
+	dataProperties, err := helpers.ConvertToStringMap(data)
+	if err != nil {
+		return err
+	}
+	return d.SetNew("data_properties", dataProperties)
+}
+
+// return the resource data from either the data or data_yaml property as a map
+// (d is either a ResourceData or ResourceDiff)
+func resourceDataMap(d interface {
+	Get(string) interface{}
+}) (map[string]interface{}, error) {
+	if dataYaml := d.Get("data_yaml").(string); dataYaml != "" {
+		data, err := helpers.YamlStringToMap(dataYaml)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal data_yaml: \n%s\nerror: %s", dataYaml, err.Error())
+		}
+		return data, nil
+	}
+	dataString := d.Get("data").(string)
+	if dataString == "" {
+		return nil, fmt.Errorf("one of 'data' or 'data_yaml' must be set")
+	}
+	data, err := helpers.JsonStringToMap(dataString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal data: \n%s\nerror: %s", dataString, err.Error())
+	}
+	return data, nil
+}
+
+// build the map of properties to read: property alias -> property path
+func resourceDataProperties(d *schema.ResourceData) (map[string]string, error) {
+	data, err := resourceDataMap(d)
+	if err != nil {
+		return nil, err
+	}
+	var properties = map[string]string{}
+	for k := range data {
+		properties[k] = k
+	}
+	return properties, nil
+}
+
+// save the data in whichever format (JSON or YAML) is used in the config, and the flattened data properties
+// NOTE: the formatted data is saved to ensure the acceptance tests behave in a consistent way regardless of the ordering of the json data
+func storeResourceData(d *schema.ResourceData, data map[string]interface{}) error {
+	if _, ok := d.GetOk("data_yaml"); ok {
+		dataYaml, err := helpers.MapToYamlString(data)
+		if err != nil {
+			return err
+		}
+		d.Set("data_yaml", dataYaml)
+	} else {
+		dataJson, err := helpers.MapToJsonString(data)
+		if err != nil {
+			return err
+		}
+		d.Set("data", dataJson)
+	}
+	dataProperties, err := helpers.ConvertToStringMap(data)
+	if err != nil {
+		return err
+	}
+	d.Set("data_properties", dataProperties)
+	return nil
+}
+
+func buildResourceInput(d *schema.ResourceData, properties []interface{}, meta interface{}) (map[string]interface{}, error) {
 	var err error
 	input := mapFromResourceData(d, properties)
-	// convert data from json string to map
-	dataString := d.Get("data").(string)
-	if input["data"], err = helpers.JsonStringToMap(dataString); err != nil {
-		return nil, fmt.Errorf("error build resource mutation input, failed to unmarshal data: \n%s\nerror: %s", dataString, err.Error())
+	applyDefaultTags(input, meta)
+	// convert data from json or yaml string to map
+	if input["data"], err = resourceDataMap(d); err != nil {
+		return nil, fmt.Errorf("error build resource mutation input, %s", err.Error())
 	}
 	// convert metadata from json string to map (if present)
 	if metadata, ok := d.GetOk("metadata"); ok {
@@ -234,6 +405,23 @@ func suppressIfAkaMatches(propertyName string) func(k, old, new string, d *schem
 
 }
 
+// data_yaml is a yaml string
+// parse old and new data then compare
+func suppressIfYamlMatches(k, old, new string, d *schema.ResourceData) bool {
+	if old == "" || new == "" {
+		return false
+	}
+	oldData, err := helpers.YamlStringToMap(old)
+	if err != nil {
+		return false
+	}
+	newData, err := helpers.YamlStringToMap(new)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(oldData, newData)
+}
+
 // data is a json string
 // apply standard formatting to old and new data then compare
 func suppressIfDataMatches(k, old, new string, d *schema.ResourceData) bool {
@@ -28,6 +28,11 @@ func TestAccResourceFolder_Basic(t *testing.T) {
 						"turbot_resource.test", "metadata", helpers.FormatJson(folderMetadata)),
 				),
 			},
+			{
+				// the config has no akas - the akas Turbot adds to the resource must not cause a diff
+				Config:   testAccResourceConfig(folderType, folderData, folderMetadata),
+				PlanOnly: true,
+			},
 			{
 				Config: testAccResourceConfig(folderType, folderDataUpdatedDescription, folderMetadata),
 				Check: resource.ComposeTestCheckFunc(
@@ -64,11 +69,172 @@ func TestAccResourceFolder_Basic(t *testing.T) {
 						"turbot_resource.test", "metadata", helpers.FormatJson(folderMetadataUpdated)),
 				),
 			},
+			{
+				// import reads the full resource data and uses the default data_mode
+				ResourceName:      "turbot_resource.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccResource_TagsAndAkas(t *testing.T) {
+	var resourceId string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceTagsConfig(`{ a = "b", c = "d" }`, `["provider_test_aka"]`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("turbot_resource.test"),
+					testAccStoreId("turbot_resource.test", &resourceId),
+					resource.TestCheckResourceAttr("turbot_resource.test", "tags.%", "2"),
+					resource.TestCheckResourceAttr("turbot_resource.test", "tags.a", "b"),
+					resource.TestCheckResourceAttr("turbot_resource.test", "akas.#", "1"),
+					resource.TestCheckResourceAttr("turbot_resource.test", "akas.0", "provider_test_aka"),
+				),
+			},
+			{
+				// the tag added outside Terraform should be detected and removed
+				PreConfig: testAccUpdateResourceOutOfBand(t, &resourceId, map[string]interface{}{"tags": map[string]interface{}{"e": "f"}}),
+				Config:    testAccResourceTagsConfig(`{ a = "b" }`, `[]`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("turbot_resource.test"),
+					resource.TestCheckResourceAttr("turbot_resource.test", "tags.%", "1"),
+					resource.TestCheckResourceAttr("turbot_resource.test", "tags.a", "b"),
+					resource.TestCheckResourceAttr("turbot_resource.test", "akas.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResource_MetadataDrift(t *testing.T) {
+	var resourceId string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceConfig(folderType, folderData, folderMetadata),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("turbot_resource.test"),
+					testAccStoreId("turbot_resource.test", &resourceId),
+					resource.TestCheckResourceAttr("turbot_resource.test", "metadata", helpers.FormatJson(folderMetadata)),
+				),
+			},
+			{
+				// metadata changed outside Terraform is detected and reverted
+				PreConfig: testAccUpdateResourceOutOfBand(t, &resourceId, map[string]interface{}{"metadata": map[string]interface{}{"c1": "changed"}}),
+				Config:    testAccResourceConfig(folderType, folderData, folderMetadata),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("turbot_resource.test"),
+					resource.TestCheckResourceAttr("turbot_resource.test", "metadata", helpers.FormatJson(folderMetadata)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResource_StrictDataMode(t *testing.T) {
+	var resourceId string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceStrictConfig(folderData),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("turbot_resource.test"),
+					testAccStoreId("turbot_resource.test", &resourceId),
+					resource.TestCheckResourceAttr("turbot_resource.test", "data_mode", "strict"),
+					resource.TestCheckResourceAttr("turbot_resource.test", "data", helpers.FormatJson(folderData)),
+				),
+			},
+			{
+				// a property added outside Terraform is detected and removed
+				PreConfig: testAccUpdateResourceOutOfBand(t, &resourceId, map[string]interface{}{"data": map[string]interface{}{"title": "provider_test", "description": "test resource", "extra": "added"}}),
+				Config:    testAccResourceStrictConfig(folderData),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("turbot_resource.test"),
+					resource.TestCheckResourceAttr("turbot_resource.test", "data", helpers.FormatJson(folderData)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResource_DataYaml(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceYamlConfig("test resource"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("turbot_resource.test"),
+					resource.TestCheckResourceAttr("turbot_resource.test", "data_properties.%", "2"),
+					resource.TestCheckResourceAttr("turbot_resource.test", "data_properties.title", "provider_test"),
+					resource.TestCheckResourceAttr("turbot_resource.test", "data_properties.description", "test resource"),
+				),
+			},
+			{
+				Config: testAccResourceYamlConfig("test resource_updated"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("turbot_resource.test"),
+					resource.TestCheckResourceAttr("turbot_resource.test", "data_properties.description", "test resource_updated"),
+				),
+			},
 		},
 	})
 }
 
 // configs
+func testAccResourceYamlConfig(description string) string {
+	return fmt.Sprintf(`
+resource "turbot_resource" "test" {
+	parent = "tmod:@turbot/turbot#/"
+	type = "%s"
+	data_yaml = <<EOF
+title: provider_test
+description: %s
+EOF
+}
+`, folderType, description)
+}
+
+func testAccResourceStrictConfig(data string) string {
+	return fmt.Sprintf(`
+resource "turbot_resource" "test" {
+	parent = "tmod:@turbot/turbot#/"
+	type = "%s"
+	data_mode = "strict"
+	data = <<EOF
+%sEOF
+	akas = ["provider_test_strict"]
+}
+`, folderType, data)
+}
+
+func testAccResourceTagsConfig(tags, akas string) string {
+	return fmt.Sprintf(`
+resource "turbot_resource" "test" {
+	parent = "tmod:@turbot/turbot#/"
+	type = "%s"
+	data = <<EOF
+%sEOF
+	tags = %s
+	akas = %s
+}
+`, folderType, folderData, tags, akas)
+}
+
 var folderType = `tmod:@turbot/turbot#/resource/types/folder`
 var folderData = `{
  "title": "provider_test",
@@ -129,6 +295,18 @@ func testAccCheckResourceExists(resource string) resource.TestCheckFunc {
 	}
 }
 
+// update the resource outside Terraform with the given input properties, e.g. tags, data or metadata
+// NOTE: the resource id is not known when the test steps are built, so it is passed by reference
+func testAccUpdateResourceOutOfBand(t *testing.T, id *string, input map[string]interface{}) func() {
+	return func() {
+		client := testAccProvider.Meta().(*apiClient.Client)
+		input["id"] = *id
+		if _, err := client.UpdateResource(input); err != nil {
+			t.Fatalf("failed to update resource %s outside Terraform: %s", *id, err)
+		}
+	}
+}
+
 func testAccCheckResourceDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(*apiClient.Client)
 	for _, rs := range s.RootModule().Resources {
@@ -36,6 +36,17 @@ func resourceTurbotSamlDirectory() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			// the version of the directory when it was last read - updates fail if it has been modified since
+			"version_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			// if set, destroying or replacing the directory fails
+			"deletion_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			"description": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -109,12 +120,16 @@ func resourceTurbotSamlDirectoryCreate(d *schema.ResourceData, meta interface{})
 	client := meta.(*apiClient.Client)
 	// build mutation payload
 	input := mapFromResourceData(d, samlDirectoryInputProperties)
+	applyDefaultTags(input, meta)
 	data := mapFromResourceData(d, samlDirectoryDataProperties)
 	// set computed properties
 	data["status"] = "Active"
 	data["directoryType"] = "saml"
 	input["data"] = data
 
+	// record the Terraform configuration which owns the saml directory
+	input["terraform"] = ownershipMetadata("turbot_saml_directory", meta)
+
 	samlDirectory, err := client.CreateSamlDirectory(input)
 	if err != nil {
 		return err
@@ -150,6 +165,7 @@ func resourceTurbotSamlDirectoryRead(d *schema.ResourceData, meta interface{}) e
 
 	// assign results back into ResourceData
 
+	d.Set("version_id", samlDirectory.Turbot.VersionId)
 	// set parent_akas property by loading parent resource and fetching the akas
 	if err := storeAkas(samlDirectory.Turbot.ParentId, "parent_akas", d, meta); err != nil {
 		return err
@@ -163,22 +179,29 @@ func resourceTurbotSamlDirectoryUpdate(d *schema.ResourceData, meta interface{})
 	client := meta.(*apiClient.Client)
 	// build mutation payload
 	input := mapFromResourceData(d, samlDirectoryInputProperties)
+	applyDefaultTags(input, meta)
 	input["data"] = mapFromResourceData(d, samlDirectoryDataProperties)
 	input["id"] = d.Id()
+	// Turbot rejects the update if the directory has been modified outside Terraform since it was last read
+	setVersionPrecondition(input, d)
 
 	// create folder returns turbot resource metadata containing the id
 	samlDirectory, err := client.UpdateSamlDirectory(input)
 	if err != nil {
-		return err
+		return versionConflictError("directory", d, err)
 	}
 	// assign Read query properties
 	d.Set("parent", samlDirectory.Parent)
 	d.Set("title", samlDirectory.Title)
+	d.Set("version_id", samlDirectory.Turbot.VersionId)
 	// set parent_akas property by loading parent resource and fetching the akas
 	return storeAkas(samlDirectory.Turbot.ParentId, "parent_akas", d, meta)
 }
 
 func resourceTurbotSamlDirectoryDelete(d *schema.ResourceData, meta interface{}) error {
+	if err := checkDeletionProtection(d, "turbot_saml_directory"); err != nil {
+		return err
+	}
 	client := meta.(*apiClient.Client)
 	id := d.Id()
 	err := client.DeleteResource(id)
@@ -192,6 +215,11 @@ func resourceTurbotSamlDirectoryDelete(d *schema.ResourceData, meta interface{})
 }
 
 func resourceTurbotSamlDirectoryImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := resolveImportId(d, meta); err != nil {
+		return nil, err
+	}
+	// set the default, so an imported resource does not show a diff
+	d.Set("deletion_protection", false)
 	if err := resourceTurbotSamlDirectoryRead(d, meta); err != nil {
 		return nil, err
 	}
@@ -1,13 +1,19 @@
 package turbot
 
 import (
+	"bytes"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/hashcode"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/terraform-providers/terraform-provider-turbot/apiClient"
 	"github.com/terraform-providers/terraform-provider-turbot/helpers"
+	"log"
+	"strings"
 )
 
 // properties which must be passed to a create/update call
-var smartFolderProperties = []interface{}{"title", "description", "parent", "filter"}
+// NOTE: filters are added separately by buildSmartFolderFilters
+var smartFolderProperties = []interface{}{"title", "description", "parent"}
 
 func getSmartFolderUpdateProperties() []interface{} {
 	excludedProperties := []string{"parent"}
@@ -41,6 +47,17 @@ func resourceTurbotSmartFolder() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			// the version of the smart folder when it was last read - updates fail if it has been modified since
+			"version_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			// if set, destroying or replacing the smart folder fails
+			"deletion_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			"title": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -50,8 +67,53 @@ func resourceTurbotSmartFolder() *schema.Resource {
 				Optional: true,
 			},
 			"filter": {
-				Type:     schema.TypeString,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Deprecated:    "use 'filters' instead",
+				ConflictsWith: []string{"filters"},
+			},
+			"filters": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			// policy settings made on the smart folder
+			"policy": {
+				Type:     schema.TypeSet,
 				Optional: true,
+				Set:      smartFolderPolicyHash,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"value": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"precedence": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "REQUIRED",
+						},
+						"template": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"note": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						// id of the policy setting
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
 			},
 		},
 	}
@@ -65,8 +127,14 @@ func resourceTurbotSmartFolderExists(d *schema.ResourceData, meta interface{}) (
 
 func resourceTurbotSmartFolderCreate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*apiClient.Client)
+	if err := checkSmartFolderFilters(d, meta); err != nil {
+		return err
+	}
 	// build map of folder properties
 	input := mapFromResourceData(d, smartFolderProperties)
+	applySmartFolderFilters(input, d, meta)
+	// record the Terraform configuration which owns the smart folder
+	input["terraform"] = ownershipMetadata("turbot_smart_folder", meta)
 
 	smartFolder, err := client.CreateSmartFolder(input)
 	if err != nil {
@@ -75,21 +143,38 @@ func resourceTurbotSmartFolderCreate(d *schema.ResourceData, meta interface{}) e
 
 	// assign the id
 	d.SetId(smartFolder.Turbot.Id)
+
+	// create the policy settings
+	if err := updateSmartFolderPolicies(d, nil, d.Get("policy").(*schema.Set).List(), meta); err != nil {
+		return err
+	}
 	// TODO Remove Read call once schema changes are In.
 	return resourceTurbotSmartFolderRead(d, meta)
 }
 
 func resourceTurbotSmartFolderUpdate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*apiClient.Client)
+	if err := checkSmartFolderFilters(d, meta); err != nil {
+		return err
+	}
 	id := d.Id()
 
 	// build map of folder properties
 	input := mapFromResourceData(d, getSmartFolderUpdateProperties())
+	applySmartFolderFilters(input, d, meta)
 	input["id"] = id
+	// Turbot rejects the update if the smart folder has been modified outside Terraform since it was last read
+	setVersionPrecondition(input, d)
 
 	_, err := client.UpdateSmartFolder(input)
 	if err != nil {
-		return err
+		return versionConflictError("smart folder", d, err)
+	}
+	if d.HasChange("policy") {
+		oldPolicies, newPolicies := d.GetChange("policy")
+		if err := updateSmartFolderPolicies(d, oldPolicies.(*schema.Set).List(), newPolicies.(*schema.Set).List(), meta); err != nil {
+			return err
+		}
 	}
 	// set 'Read' Properties
 	// TODO Remove Read call once schema changes are In.
@@ -110,24 +195,42 @@ func resourceTurbotSmartFolderRead(d *schema.ResourceData, meta interface{}) err
 	}
 
 	// assign results back into ResourceData
+	d.Set("version_id", smartFolder.Turbot.VersionId)
 	// set parent_akas property by loading resource and fetching the akas
 	if err := storeAkas(smartFolder.Turbot.ParentId, "parent_akas", d, meta); err != nil {
 		return err
 	}
-	// NOTE currently turbot accepts array of filters but only uses the first
-	if len(smartFolder.Filters) > 0 {
-		d.Set("filter", smartFolder.Filters[0])
+	// if the deprecated 'filter' property is used, it must hold the only filter, otherwise set 'filters'
+	if _, ok := d.GetOk("filter"); ok {
+		switch len(smartFolder.Filters) {
+		case 0:
+			d.Set("filter", "")
+		case 1:
+			d.Set("filter", smartFolder.Filters[0])
+		default:
+			return fmt.Errorf("smart folder %s has %d filters, which cannot be represented by the deprecated 'filter' argument. Use 'filters' instead", id, len(smartFolder.Filters))
+		}
+	} else {
+		d.Set("filters", smartFolder.Filters)
 	}
 	d.Set("parent_id", smartFolder.Parent)
 	d.Set("title", smartFolder.Title)
 	d.Set("description", smartFolder.Description)
 
-	return nil
+	// read the policy settings on the smart folder
+	return readSmartFolderPolicies(d, meta)
 }
 
 func resourceTurbotSmartFolderDelete(d *schema.ResourceData, meta interface{}) error {
+	if err := checkDeletionProtection(d, "turbot_smart_folder"); err != nil {
+		return err
+	}
 	client := meta.(*apiClient.Client)
 	id := d.Id()
+	// delete the policy settings first
+	if err := updateSmartFolderPolicies(d, d.Get("policy").(*schema.Set).List(), nil, meta); err != nil {
+		return err
+	}
 	err := client.DeleteResource(id)
 	if err != nil {
 		return err
@@ -140,8 +243,176 @@ func resourceTurbotSmartFolderDelete(d *schema.ResourceData, meta interface{}) e
 }
 
 func resourceTurbotSmartFolderImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := resolveImportId(d, meta); err != nil {
+		return nil, err
+	}
+	// set the default, so an imported resource does not show a diff
+	d.Set("deletion_protection", false)
 	if err := resourceTurbotSmartFolderRead(d, meta); err != nil {
 		return nil, err
 	}
 	return []*schema.ResourceData{d}, nil
 }
+
+// build the list of filters from either 'filters' or the deprecated 'filter' property
+// filters and description are only passed if the workspace supports them
+// the filters are always passed so that removed filters are cleared
+func applySmartFolderFilters(input map[string]interface{}, d *schema.ResourceData, meta interface{}) {
+	if meta.(*apiClient.Client).CanSetSmartFolderFilters() {
+		input["filters"] = buildSmartFolderFilters(d)
+	} else {
+		delete(input, "description")
+	}
+}
+
+func buildSmartFolderFilters(d *schema.ResourceData) []string {
+	if filter, ok := d.GetOk("filter"); ok {
+		return []string{filter.(string)}
+	}
+	var filters = []string{}
+	for _, filter := range d.Get("filters").([]interface{}) {
+		filters = append(filters, filter.(string))
+	}
+	return filters
+}
+
+// policy settings
+
+// the properties of a policy block passed to the create/update policy setting mutations
+var smartFolderPolicyProperties = []string{"value", "precedence", "template", "note"}
+
+// hash a policy block - the computed id is excluded so the hash is the same before and after creation
+func smartFolderPolicyHash(v interface{}) int {
+	var buf bytes.Buffer
+	policy := v.(map[string]interface{})
+	buf.WriteString(fmt.Sprintf("%s-", policy["type"].(string)))
+	for _, property := range smartFolderPolicyProperties {
+		if value, ok := policy[property]; ok {
+			buf.WriteString(fmt.Sprintf("%s-", value.(string)))
+		}
+	}
+	return hashcode.String(buf.String())
+}
+
+// create, update and delete policy settings so the settings on the smart folder match newPolicies
+func updateSmartFolderPolicies(d *schema.ResourceData, oldPolicies, newPolicies []interface{}, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	oldPoliciesByType, err := smartFolderPoliciesByType(oldPolicies)
+	if err != nil {
+		return err
+	}
+	newPoliciesByType, err := smartFolderPoliciesByType(newPolicies)
+	if err != nil {
+		return err
+	}
+
+	// delete settings which have been removed
+	for policyType, oldPolicy := range oldPoliciesByType {
+		if _, ok := newPoliciesByType[policyType]; ok {
+			continue
+		}
+		if id := oldPolicy["id"].(string); id != "" {
+			if err := client.DeletePolicySetting(id); err != nil && !apiClient.NotFoundError(err) {
+				return err
+			}
+		}
+	}
+
+	// create new settings and update changed settings
+	for policyType, newPolicy := range newPoliciesByType {
+		input := map[string]interface{}{}
+		for _, property := range smartFolderPolicyProperties {
+			if value := newPolicy[property].(string); value != "" {
+				input[property] = value
+			}
+		}
+		if oldPolicy, ok := oldPoliciesByType[policyType]; ok && oldPolicy["id"].(string) != "" {
+			if smartFolderPolicyHash(oldPolicy) == smartFolderPolicyHash(newPolicy) {
+				continue
+			}
+			input["id"] = oldPolicy["id"]
+			if _, err := writePolicySetting(client.UpdatePolicySetting, input); err != nil {
+				return err
+			}
+		} else {
+			input["type"] = policyType
+			input["resource"] = d.Id()
+			if _, err := writePolicySetting(client.CreatePolicySetting, input); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// build a map of policy blocks keyed by policy type, returning an error if a type is used more than once
+func smartFolderPoliciesByType(policies []interface{}) (map[string]map[string]interface{}, error) {
+	var policiesByType = map[string]map[string]interface{}{}
+	for _, p := range policies {
+		policy := p.(map[string]interface{})
+		policyType := policy["type"].(string)
+		if _, ok := policiesByType[policyType]; ok {
+			return nil, fmt.Errorf("policy type %s is specified more than once", policyType)
+		}
+		policiesByType[policyType] = policy
+	}
+	return policiesByType, nil
+}
+
+// create or update a policy setting
+// as we are not sure of the value format provided, if the value fails validation, retry passing it as valueSource
+func writePolicySetting(write func(map[string]interface{}) (*apiClient.PolicySetting, error), input map[string]interface{}) (*apiClient.PolicySetting, error) {
+	policySetting, err := write(input)
+	if err == nil || !apiClient.FailedValidationError(err) || input["value"] == nil {
+		return policySetting, err
+	}
+	input["valueSource"] = input["value"]
+	delete(input, "value")
+	return write(input)
+}
+
+// read the policy settings on the smart folder into the 'policy' property
+// settings made outside Terraform are included, so they are reported as a diff
+func readSmartFolderPolicies(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	settings, err := client.ReadResourcePolicySettings(d.Id())
+	if err != nil {
+		return err
+	}
+	statePoliciesByType, err := smartFolderPoliciesByType(d.Get("policy").(*schema.Set).List())
+	if err != nil {
+		return err
+	}
+
+	var policies []interface{}
+	for _, setting := range settings {
+		policyType := setting.Type.Uri
+		value := setting.ValueSource
+		if statePolicy, ok := statePoliciesByType[policyType]; ok {
+			// if the value in state is equivalent to the setting value, keep it to avoid a diff due to formatting
+			stateValue := strings.TrimSpace(statePolicy["value"].(string))
+			if stateValue == settingValueToString(setting.Value) || stateValue == strings.TrimSpace(setting.ValueSource) {
+				value = statePolicy["value"].(string)
+			}
+		} else {
+			log.Printf("[WARN] smart folder %s has a policy setting for %s which is not declared in the configuration", d.Id(), policyType)
+		}
+		policies = append(policies, map[string]interface{}{
+			"type":       policyType,
+			"value":      value,
+			"precedence": setting.Precedence,
+			"template":   setting.Template,
+			"note":       setting.Note,
+			"id":         setting.Turbot.Id,
+		})
+	}
+	return d.Set("policy", schema.NewSet(smartFolderPolicyHash, policies))
+}
+
+// fail with a clear error if filters or description are set but the workspace does not support them
+func checkSmartFolderFilters(d *schema.ResourceData, meta interface{}) error {
+	if len(buildSmartFolderFilters(d)) == 0 && d.Get("description").(string) == "" {
+		return nil
+	}
+	return meta.(*apiClient.Client).CheckSmartFolderFilters()
+}
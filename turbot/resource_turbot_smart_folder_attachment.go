@@ -1,9 +1,9 @@
 package turbot
 
 import (
-	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/terraform-providers/terraform-provider-turbot/apiClient"
+	"log"
 	"strings"
 )
 
@@ -38,25 +38,9 @@ func resourceTurbotSmartFolderAttachemnt() *schema.Resource {
 
 func resourceTurbotSmartFolderAttachmentExists(d *schema.ResourceData, meta interface{}) (b bool, e error) {
 	client := meta.(*apiClient.Client)
-	smartFolderId, resource := parseSmartFolderId(d.Id())
-	// execute api call
-	smartFolder, err := client.ReadSmartFolder(smartFolderId)
-	if err != nil {
-		return false, fmt.Errorf("error reading smart folder: %s", err.Error())
-	}
-
-	//find resource aka in list of attached resources
-	for _, attachedResource := range smartFolder.AttachedResources.Items {
-		if resource == attachedResource.Turbot.Id {
-			return true, nil
-		}
-		for _, aka := range attachedResource.Turbot.Akas {
-			if aka == resource {
-				return true, nil
-			}
-		}
-	}
-	return false, nil
+	smartFolderId, _ := parseSmartFolderId(d.Id())
+	// NOTE: this only checks the smart folder exists - Read checks whether the resource is still attached
+	return client.ResourceExists(smartFolderId)
 }
 
 func resourceTurbotSmartFolderAttachmentCreate(d *schema.ResourceData, meta interface{}) error {
@@ -79,8 +63,25 @@ func resourceTurbotSmartFolderAttachmentCreate(d *schema.ResourceData, meta inte
 }
 
 func resourceTurbotSmartFolderAttachmentRead(d *schema.ResourceData, meta interface{}) error {
-	// NOTE: This will not be called if the attachment does not exist
+	client := meta.(*apiClient.Client)
 	smartFolder, resource := parseSmartFolderId(d.Id())
+
+	// check the resource is still attached to the smart folder
+	attached, err := client.SmartFolderAttachmentExists(smartFolder, resource)
+	if err != nil {
+		if apiClient.NotFoundError(err) {
+			// smart folder was not found - clear id
+			d.SetId("")
+		}
+		return err
+	}
+	if !attached {
+		// the attachment was removed outside Terraform - clear id so it is recreated
+		log.Printf("[WARN] resource %s is no longer attached to smart folder %s, removing from state", resource, smartFolder)
+		d.SetId("")
+		return nil
+	}
+
 	// assign results directly back into ResourceData
 	d.Set("resource", resource)
 	d.Set("smart_folder", smartFolder)
@@ -112,7 +113,8 @@ func buildId(smartFolder, resource string) string {
 }
 
 func parseSmartFolderId(id string) (smartFolder, resource string) {
-	segments := strings.Split(id, "_")
+	// NOTE: the resource may be an aka containing underscores - the smart folder is always an id so split on the first underscore
+	segments := strings.SplitN(id, "_", 2)
 	smartFolder = segments[0]
 	resource = segments[1]
 	return
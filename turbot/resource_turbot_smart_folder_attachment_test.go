@@ -25,6 +25,33 @@ func TestAccSmartFolderAttachment_Basic(t *testing.T) {
 	})
 }
 
+func TestAccSmartFolderAttachment_DetachedOutsideTerraform(t *testing.T) {
+	var attachmentId string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSmartFolderAttachmentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSmartFolderAttachmentConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSmartFolderAttachmentExists("turbot_smart_folder_attachment.test"),
+					testAccStoreId("turbot_smart_folder_attachment.test", &attachmentId),
+				),
+			},
+			{
+				// detach the resource outside terraform - the next plan should recreate the attachment
+				PreConfig: func() {
+					testAccDetachSmartFolder(t, attachmentId)
+				},
+				Config:             testAccSmartFolderAttachmentConfig(),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
 // configs
 func testAccSmartFolderAttachmentConfig() string {
 	return `
@@ -60,13 +87,28 @@ func testAccCheckSmartFolderAttachmentExists(resource string) resource.TestCheck
 		}
 		client := testAccProvider.Meta().(*apiClient.Client)
 		smartFolderId, resource := parseSmartFolderId(rs.Primary.ID)
-		_, err := client.ReadSmartFolder(smartFolderId)
+		attached, err := client.SmartFolderAttachmentExists(smartFolderId, resource)
 		if err != nil {
 			return fmt.Errorf("error fetching item with resource %s. %s", resource, err)
 		}
+		if !attached {
+			return fmt.Errorf("resource %s is not attached to smart folder %s", resource, smartFolderId)
+		}
 		return nil
 	}
 }
+
+func testAccDetachSmartFolder(t *testing.T, id string) {
+	client := testAccProvider.Meta().(*apiClient.Client)
+	smartFolderId, resource := parseSmartFolderId(id)
+	input := map[string]interface{}{
+		"resource":     resource,
+		"smartFolders": smartFolderId,
+	}
+	if err := client.DeleteSmartFolderAttachment(input); err != nil {
+		t.Fatalf("failed to detach smart folder: %s", err)
+	}
+}
 func testAccCheckSmartFolderAttachmentDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(*apiClient.Client)
 	for _, rs := range s.RootModule().Resources {
@@ -0,0 +1,271 @@
+package turbot
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/terraform-providers/terraform-provider-turbot/apiClient"
+	"log"
+)
+
+// turbot_smart_folder_attachments manages the complete set of resources attached to a smart folder.
+// Resources are either listed explicitly, or selected using a resource list filter.
+// Any other attachments are reported as drift and detached on the next apply.
+func resourceTurbotSmartFolderAttachments() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTurbotSmartFolderAttachmentsCreate,
+		Read:   resourceTurbotSmartFolderAttachmentsRead,
+		Update: resourceTurbotSmartFolderAttachmentsUpdate,
+		Delete: resourceTurbotSmartFolderAttachmentsDelete,
+		Exists: resourceTurbotSmartFolderAttachmentsExists,
+		Importer: &schema.ResourceImporter{
+			State: resourceTurbotSmartFolderAttachmentsImport,
+		},
+		CustomizeDiff: resourceTurbotSmartFolderAttachmentsCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"smart_folder": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				// when doing a diff, the state file will contain the id of the smart folder but the config may contain an aka,
+				// so we need custom diff code
+				DiffSuppressFunc: suppressIfAkaMatches("smart_folder_akas"),
+			},
+			// when doing a read, fetch the smart folder akas to use in suppressIfAkaMatches
+			"smart_folder_akas": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			// ids or akas of the resources to attach - removing a resource from the list detaches it
+			"resources": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				ConflictsWith: []string{"filter"},
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Set: schema.HashString,
+			},
+			// resource list filter selecting the resources to attach
+			"filter": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			// if a filter is used, the ids of the resources matching the filter
+			"matched_resources": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Set: schema.HashString,
+			},
+		},
+	}
+}
+
+func resourceTurbotSmartFolderAttachmentsExists(d *schema.ResourceData, meta interface{}) (b bool, e error) {
+	client := meta.(*apiClient.Client)
+	return client.ResourceExists(d.Id())
+}
+
+// if a filter is used, refresh the list of resources matching the filter so changes show in the plan
+func resourceTurbotSmartFolderAttachmentsCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	filter := d.Get("filter").(string)
+	if filter == "" {
+		return nil
+	}
+	matchingResources, err := smartFolderFilterResources(filter, meta)
+	if err != nil {
+		return err
+	}
+	current := d.Get("matched_resources").(*schema.Set)
+	desired := schema.NewSet(schema.HashString, stringsToInterfaces(matchingResources))
+	if !current.Equal(desired) {
+		return d.SetNew("matched_resources", desired.List())
+	}
+	return nil
+}
+
+func resourceTurbotSmartFolderAttachmentsCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	smartFolder := d.Get("smart_folder").(string)
+	// resolve the smart folder id, as this is used to read the attachments
+	resource, err := client.ReadResource(smartFolder, nil)
+	if err != nil {
+		return err
+	}
+	d.SetId(resource.Turbot.Id)
+
+	if err := syncSmartFolderAttachments(d, meta); err != nil {
+		return err
+	}
+	return resourceTurbotSmartFolderAttachmentsRead(d, meta)
+}
+
+func resourceTurbotSmartFolderAttachmentsUpdate(d *schema.ResourceData, meta interface{}) error {
+	if err := syncSmartFolderAttachments(d, meta); err != nil {
+		return err
+	}
+	return resourceTurbotSmartFolderAttachmentsRead(d, meta)
+}
+
+func resourceTurbotSmartFolderAttachmentsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	id := d.Id()
+
+	attachedResources, err := client.ReadSmartFolderAttachedResources(id)
+	if err != nil {
+		if apiClient.NotFoundError(err) {
+			// smart folder was not found - clear id
+			d.SetId("")
+		}
+		return err
+	}
+
+	// build the list of attached resources, using the id or aka from the existing state where possible
+	// so that resources specified by aka do not cause a diff.
+	// attachments which are not in state (i.e. made outside Terraform) are added using their id, and so show as drift
+	var resources []interface{}
+	existingResources := d.Get("resources").(*schema.Set).List()
+	for _, attachedResource := range attachedResources {
+		resource := attachedResource.Id
+		for _, existing := range existingResources {
+			if apiClient.ResourceMatches(attachedResource, existing.(string)) {
+				resource = existing.(string)
+				break
+			}
+		}
+		resources = append(resources, resource)
+	}
+
+	d.Set("smart_folder", id)
+	// if a filter is used, the attached resources are compared with the resources matching the filter
+	if d.Get("filter").(string) != "" {
+		d.Set("matched_resources", schema.NewSet(schema.HashString, resources))
+	} else {
+		d.Set("resources", schema.NewSet(schema.HashString, resources))
+	}
+	// set smart_folder_akas property by loading the smart folder and fetching the akas
+	return storeAkas(id, "smart_folder_akas", d, meta)
+}
+
+func resourceTurbotSmartFolderAttachmentsDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	id := d.Id()
+
+	// detach all resources which are currently attached
+	attachedResources, err := client.ReadSmartFolderAttachedResources(id)
+	if err != nil {
+		return err
+	}
+	var resources []string
+	for _, attachedResource := range attachedResources {
+		resources = append(resources, attachedResource.Id)
+	}
+	if err := client.DetachSmartFolderResources(id, resources); err != nil {
+		return err
+	}
+
+	// clear the id to show we have deleted
+	d.SetId("")
+	return nil
+}
+
+func resourceTurbotSmartFolderAttachmentsImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := resolveImportId(d, meta); err != nil {
+		return nil, err
+	}
+	if err := resourceTurbotSmartFolderAttachmentsRead(d, meta); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// attach the resources which should be attached but are not, and detach any other attached resources
+func syncSmartFolderAttachments(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*apiClient.Client)
+	id := d.Id()
+
+	desiredResources, err := desiredSmartFolderResources(d, meta)
+	if err != nil {
+		return err
+	}
+	attachedResources, err := client.ReadSmartFolderAttachedResources(id)
+	if err != nil {
+		return err
+	}
+
+	var toAttach []string
+	for _, resource := range desiredResources {
+		if !resourceInList(resource, attachedResources) {
+			toAttach = append(toAttach, resource)
+		}
+	}
+	var toDetach []string
+	for _, attachedResource := range attachedResources {
+		if !resourceMatchesAny(attachedResource, desiredResources) {
+			toDetach = append(toDetach, attachedResource.Id)
+		}
+	}
+
+	log.Printf("[INFO] smart folder %s: attaching %d resources, detaching %d resources", id, len(toAttach), len(toDetach))
+	if err := client.AttachSmartFolderResources(id, toAttach); err != nil {
+		return err
+	}
+	return client.DetachSmartFolderResources(id, toDetach)
+}
+
+// the resources which should be attached - either the configured list or the resources matching the filter
+func desiredSmartFolderResources(d *schema.ResourceData, meta interface{}) ([]string, error) {
+	if filter := d.Get("filter").(string); filter != "" {
+		return smartFolderFilterResources(filter, meta)
+	}
+	var resources []string
+	for _, resource := range d.Get("resources").(*schema.Set).List() {
+		resources = append(resources, resource.(string))
+	}
+	return resources, nil
+}
+
+// return the ids of all resources matching the filter
+func smartFolderFilterResources(filter string, meta interface{}) ([]string, error) {
+	client := meta.(*apiClient.Client)
+	resources, err := client.ReadResourceListAll(filter, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error reading resources matching filter '%s': %s", filter, err.Error())
+	}
+	var ids []string
+	for _, resource := range resources {
+		ids = append(ids, resource.Turbot.Id)
+	}
+	return ids, nil
+}
+
+func resourceInList(resource string, resources []apiClient.TurbotResourceMetadata) bool {
+	for _, r := range resources {
+		if apiClient.ResourceMatches(r, resource) {
+			return true
+		}
+	}
+	return false
+}
+
+func resourceMatchesAny(resource apiClient.TurbotResourceMetadata, resources []string) bool {
+	for _, r := range resources {
+		if apiClient.ResourceMatches(resource, r) {
+			return true
+		}
+	}
+	return false
+}
+
+func stringsToInterfaces(values []string) []interface{} {
+	var result []interface{}
+	for _, v := range values {
+		result = append(result, v)
+	}
+	return result
+}
@@ -0,0 +1,129 @@
+package turbot
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/terraform-providers/terraform-provider-turbot/apiClient"
+	"testing"
+)
+
+// test suites
+func TestAccSmartFolderAttachments_Basic(t *testing.T) {
+	var smartFolderId, folderId string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSmartFolderAttachmentsDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSmartFolderAttachmentsConfig(2),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSmartFolderAttachmentsCount("turbot_smart_folder_attachments.test", 2),
+					resource.TestCheckResourceAttr("turbot_smart_folder_attachments.test", "resources.#", "2"),
+				),
+			},
+			{
+				Config: testAccSmartFolderAttachmentsConfig(1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSmartFolderAttachmentsCount("turbot_smart_folder_attachments.test", 1),
+					resource.TestCheckResourceAttr("turbot_smart_folder_attachments.test", "resources.#", "1"),
+					testAccStoreId("turbot_smart_folder_attachments.test", &smartFolderId),
+					testAccStoreId("turbot_folder.test.1", &folderId),
+				),
+			},
+			{
+				// a resource attached outside Terraform is reported as drift and detached
+				PreConfig: testAccAttachSmartFolderResource(t, &smartFolderId, &folderId),
+				Config:    testAccSmartFolderAttachmentsConfig(1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSmartFolderAttachmentsCount("turbot_smart_folder_attachments.test", 1),
+					resource.TestCheckResourceAttr("turbot_smart_folder_attachments.test", "resources.#", "1"),
+				),
+			},
+			{
+				// an empty list detaches every resource
+				Config: testAccSmartFolderAttachmentsConfig(0),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSmartFolderAttachmentsCount("turbot_smart_folder_attachments.test", 0),
+					resource.TestCheckResourceAttr("turbot_smart_folder_attachments.test", "resources.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+// configs
+func testAccSmartFolderAttachmentsConfig(count int) string {
+	return fmt.Sprintf(`
+resource "turbot_folder" "test" {
+  count = 2
+  parent = "tmod:@turbot/turbot#/"
+  title = "provider_test_${count.index}"
+  description = "test folder"
+}
+
+resource "turbot_smart_folder" "test" {
+  parent  = "tmod:@turbot/turbot#/"
+  description = "Smart Folder Testing"
+  title = "smart_folder"
+}
+
+resource "turbot_smart_folder_attachments" "test" {
+  smart_folder = turbot_smart_folder.test.id
+  resources = slice(turbot_folder.test.*.id, 0, %d)
+}
+`, count)
+}
+
+// helper functions
+// attach a resource to the smart folder outside Terraform
+func testAccAttachSmartFolderResource(t *testing.T, smartFolderId, resourceId *string) func() {
+	return func() {
+		client := testAccProvider.Meta().(*apiClient.Client)
+		if err := client.AttachSmartFolderResources(*smartFolderId, []string{*resourceId}); err != nil {
+			t.Fatalf("failed to attach resource %s outside Terraform: %s", *resourceId, err)
+		}
+	}
+}
+
+func testAccCheckSmartFolderAttachmentsCount(resource string, expected int) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[resource]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resource)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No Record ID is set")
+		}
+		client := testAccProvider.Meta().(*apiClient.Client)
+		attachedResources, err := client.ReadSmartFolderAttachedResources(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if len(attachedResources) != expected {
+			return fmt.Errorf("expected %d attached resources, got %d", expected, len(attachedResources))
+		}
+		return nil
+	}
+}
+
+func testAccCheckSmartFolderAttachmentsDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*apiClient.Client)
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "turbot_smart_folder_attachments" {
+			continue
+		}
+		attachedResources, err := client.ReadSmartFolderAttachedResources(rs.Primary.ID)
+		if err != nil {
+			if apiClient.NotFoundError(err) {
+				continue
+			}
+			return err
+		}
+		if len(attachedResources) > 0 {
+			return fmt.Errorf("smart folder %s still has %d attached resources", rs.Primary.ID, len(attachedResources))
+		}
+	}
+	return nil
+}
@@ -38,7 +38,110 @@ func TestAccSmartFolder_Basic(t *testing.T) {
 	})
 }
 
+func TestAccSmartFolder_Filters(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSmartFolderDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSmartFolderFiltersConfig(`["resourceType:181381985925765 $.turbot.tags.a:b", "resourceType:181381985925765 $.turbot.tags.c:d"]`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSmartFolderExists("turbot_smart_folder.test"),
+					resource.TestCheckResourceAttr("turbot_smart_folder.test", "filters.#", "2"),
+					resource.TestCheckResourceAttr("turbot_smart_folder.test", "filters.0", "resourceType:181381985925765 $.turbot.tags.a:b"),
+					resource.TestCheckResourceAttr("turbot_smart_folder.test", "filters.1", "resourceType:181381985925765 $.turbot.tags.c:d"),
+				),
+			},
+			{
+				Config: testAccSmartFolderFiltersConfig(`["resourceType:181381985925765 $.turbot.tags.c:d"]`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSmartFolderExists("turbot_smart_folder.test"),
+					resource.TestCheckResourceAttr("turbot_smart_folder.test", "filters.#", "1"),
+					resource.TestCheckResourceAttr("turbot_smart_folder.test", "filters.0", "resourceType:181381985925765 $.turbot.tags.c:d"),
+				),
+			},
+			{
+				Config: testAccSmartFolderFiltersConfig(`[]`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSmartFolderExists("turbot_smart_folder.test"),
+					resource.TestCheckResourceAttr("turbot_smart_folder.test", "filters.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSmartFolder_Policies(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSmartFolderDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSmartFolderPoliciesConfig("test"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSmartFolderExists("turbot_smart_folder.test"),
+					testAccCheckSmartFolderPolicyCount("turbot_smart_folder.test", 2),
+					resource.TestCheckResourceAttr("turbot_smart_folder.test", "policy.#", "2"),
+				),
+			},
+			{
+				Config: testAccSmartFolderPoliciesConfig("updated"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSmartFolderPolicyCount("turbot_smart_folder.test", 2),
+					resource.TestCheckResourceAttr("turbot_smart_folder.test", "policy.#", "2"),
+				),
+			},
+			{
+				Config: testAccSmartFolderConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSmartFolderPolicyCount("turbot_smart_folder.test", 0),
+					resource.TestCheckResourceAttr("turbot_smart_folder.test", "policy.#", "0"),
+				),
+			},
+		},
+	})
+}
+
 // configs
+func testAccSmartFolderPoliciesConfig(stringPolicyValue string) string {
+	return fmt.Sprintf(`
+resource "turbot_smart_folder" "test" {
+	parent  = "tmod:@turbot/turbot#/"
+	filter = "resourceType:181381985925765 $.turbot.tags.a:b"
+	description = "Smart Folder Testing"
+	title = "smart_folder"
+
+	policy {
+		type  = "%s"
+		value = <<EOT
+- a
+- b
+EOT
+		note  = "managed by terraform"
+	}
+
+	policy {
+		type       = "%s"
+		value      = "%s"
+		precedence = "RECOMMENDED"
+	}
+}
+`, stringArrayPolicyType, stringPolicyType, stringPolicyValue)
+}
+
+func testAccSmartFolderFiltersConfig(filters string) string {
+	return fmt.Sprintf(`
+resource "turbot_smart_folder" "test" {
+	parent  = "tmod:@turbot/turbot#/"
+	filters = %s
+	description = "Smart Folder Testing"
+	title = "smart_folder"
+}
+`, filters)
+}
+
 func testAccSmartFolderConfig() string {
 	return `
 resource "turbot_smart_folder" "test" {
@@ -96,3 +199,21 @@ func testAccCheckSmartFolderDestroy(s *terraform.State) error {
 
 	return nil
 }
+
+func testAccCheckSmartFolderPolicyCount(resource string, expected int) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[resource]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resource)
+		}
+		client := testAccProvider.Meta().(*apiClient.Client)
+		settings, err := client.ReadResourcePolicySettings(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if len(settings) != expected {
+			return fmt.Errorf("expected %d policy settings, got %d", expected, len(settings))
+		}
+		return nil
+	}
+}